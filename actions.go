@@ -0,0 +1,77 @@
+package messenger
+
+import (
+	"context"
+	"net/http"
+)
+
+// SenderAction shows typing indicator or marks last message as seen
+type SenderAction string
+
+const (
+	// SenderActionTypingOn shows typing indicator, it is turned off after 20 seconds or when message is sent
+	SenderActionTypingOn = SenderAction("typing_on")
+
+	// SenderActionTypingOff hides typing indicator
+	SenderActionTypingOff = SenderAction("typing_off")
+
+	// SenderActionMarkSeen marks last message as read
+	SenderActionMarkSeen = SenderAction("mark_seen")
+)
+
+// SendAction sends sender action to recipientID
+func (msng *Messenger) SendAction(ctx context.Context, recipientID string, a SenderAction) error {
+	body := struct {
+		Recipient    recipient    `json:"recipient"`
+		SenderAction SenderAction `json:"sender_action"`
+	}{newRecipient(recipientID), a}
+	return msng.graphRequest(ctx, http.MethodPost, "me/messages", nil, body, nil)
+}
+
+// MarkSeen marks last message from userID as seen
+func (msng *Messenger) MarkSeen(ctx context.Context, userID string) error {
+	return msng.SendAction(ctx, userID, SenderActionMarkSeen)
+}
+
+// TypingOn shows typing indicator to userID
+func (msng *Messenger) TypingOn(ctx context.Context, userID string) error {
+	return msng.SendAction(ctx, userID, SenderActionTypingOn)
+}
+
+// TypingOff hides typing indicator from userID
+func (msng *Messenger) TypingOff(ctx context.Context, userID string) error {
+	return msng.SendAction(ctx, userID, SenderActionTypingOff)
+}
+
+// SendWithTypingIndicator shows typing indicator to recipientID while processFn prepares the message,
+// then sends the message returned by processFn to recipientID. If processFn fails or ctx is cancelled meanwhile,
+// typing indicator is turned off and error is returned
+func (msng *Messenger) SendWithTypingIndicator(ctx context.Context, recipientID string, processFn func() (Message, error)) (FacebookResponse, error) {
+	if err := msng.SendAction(ctx, recipientID, SenderActionTypingOn); err != nil {
+		return FacebookResponse{}, err
+	}
+
+	type result struct {
+		m   Message
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		m, err := processFn()
+		done <- result{m, err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res.err = ctx.Err()
+	}
+
+	// ctx might be cancelled already, typing indicator should still be turned off
+	msng.SendAction(context.WithoutCancel(ctx), recipientID, SenderActionTypingOff)
+	if res.err != nil {
+		return FacebookResponse{}, res.err
+	}
+	return msng.sendMessage(ctx, res.m, []SendOption{withRecipient(recipientID)})
+}
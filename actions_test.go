@@ -0,0 +1,116 @@
+package messenger_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestSendWithTypingIndicator(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var sent []string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Recipient struct {
+				ID string `json:"id"`
+			} `json:"recipient"`
+			SenderAction string `json:"sender_action"`
+			Message      struct {
+				Text string `json:"text"`
+			} `json:"message"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Recipient.ID != "123" {
+			t.Error("Expected recipient 123, sent to", body.Recipient.ID)
+		}
+		mu.Lock()
+		if body.SenderAction != "" {
+			sent = append(sent, body.SenderAction)
+		} else {
+			sent = append(sent, body.Message.Text)
+		}
+		mu.Unlock()
+		w.Write([]byte(`{"recipient_id":"123","message_id":"mid.1"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	ctx := context.Background()
+	resp, err := msng.SendWithTypingIndicator(ctx, "123", func() (messenger.Message, error) {
+		m := msng.NewTextMessage(0, "done") // recipient is set by SendWithTypingIndicator
+		return &m, nil
+	})
+	if err != nil || resp.MessageID != "mid.1" {
+		t.Fatal("Unexpected response", resp, err)
+	}
+	expectSent(t, sent, "typing_on", "typing_off", "done")
+
+	sent = nil
+	processErr := errors.New("failed")
+	if _, err := msng.SendWithTypingIndicator(ctx, "123", func() (messenger.Message, error) { return nil, processErr }); err != processErr {
+		t.Error("Expected process error, returned", err)
+	}
+	expectSent(t, sent, "typing_on", "typing_off")
+
+	sent = nil
+	cctx, cancel := context.WithCancel(ctx)
+	release := make(chan struct{})
+	defer close(release)
+	_, err = msng.SendWithTypingIndicator(cctx, "123", func() (messenger.Message, error) {
+		cancel()
+		<-release
+		return nil, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Error("Expected context.Canceled, returned", err)
+	}
+	expectSent(t, sent, "typing_on", "typing_off")
+}
+
+func TestSenderActionShorthands(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var sent []string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Recipient struct {
+				ID string `json:"id"`
+			} `json:"recipient"`
+			SenderAction string `json:"sender_action"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Recipient.ID != "123" {
+			t.Error("Unexpected recipient", body.Recipient.ID)
+		}
+		mu.Lock()
+		sent = append(sent, body.SenderAction)
+		mu.Unlock()
+		w.Write([]byte(`{"recipient_id":"123"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	ctx := context.Background()
+	for _, action := range []func(context.Context, string) error{msng.MarkSeen, msng.TypingOn, msng.TypingOff} {
+		if err := action(ctx, "123"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectSent(t, sent, "mark_seen", "typing_on", "typing_off")
+}
+
+func expectSent(t *testing.T, sent []string, expected ...string) {
+	t.Helper()
+	if len(sent) != len(expected) {
+		t.Fatal("Expected", expected, "sent", sent)
+	}
+	for i := range expected {
+		if sent[i] != expected[i] {
+			t.Error("Expected", expected, "sent", sent)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package messenger
+
+import "net/url"
+
+// AnalyticsSendConfig describes campaign that sent message, see WithAnalytics
+type AnalyticsSendConfig struct {
+	CampaignID   string
+	CampaignName string // for bot's own bookkeeping, not added to URLs
+	Source       string // "messenger" if empty
+	Medium       string // "bot" if empty
+}
+
+// WithAnalytics adds UTM parameters to all URLs in the message (buttons, element links...),
+// so clicks can be attributed to campaign
+func WithAnalytics(cfg AnalyticsSendConfig) SendOption {
+	b := UTMBuilder{
+		Source:   cfg.Source,
+		Medium:   cfg.Medium,
+		Campaign: cfg.CampaignID,
+	}
+	return func(o *sendOptions) {
+		o.transform(func(body map[string]interface{}) error {
+			return b.applyAll(body)
+		})
+	}
+}
+
+// UTMBuilder adds UTM query parameters to URLs
+type UTMBuilder struct {
+	Source   string // utm_source, "messenger" if empty
+	Medium   string // utm_medium, "bot" if empty
+	Campaign string // utm_campaign
+}
+
+// Values returns UTM query parameters, empty parameters are omitted
+func (b UTMBuilder) Values() url.Values {
+	v := url.Values{}
+	v.Set("utm_source", b.Source)
+	if b.Source == "" {
+		v.Set("utm_source", "messenger")
+	}
+	v.Set("utm_medium", b.Medium)
+	if b.Medium == "" {
+		v.Set("utm_medium", "bot")
+	}
+	if b.Campaign != "" {
+		v.Set("utm_campaign", b.Campaign)
+	}
+	return v
+}
+
+// Apply adds UTM parameters to rawURL. Existing query parameters are preserved as they are,
+// including UTM parameters already present in the URL
+func (b UTMBuilder) Apply(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	existing := u.Query()
+	add := url.Values{}
+	for k, v := range b.Values() {
+		if _, ok := existing[k]; !ok {
+			add[k] = v
+		}
+	}
+	if len(add) == 0 {
+		return rawURL, nil
+	}
+
+	if u.RawQuery == "" {
+		u.RawQuery = add.Encode()
+	} else {
+		u.RawQuery += "&" + add.Encode()
+	}
+	return u.String(), nil
+}
+
+// urlFields are message JSON fields that contain links user can open. Field url is a link only in
+// URL buttons and default actions, in attachment payloads it is media URL
+var urlFields = map[string]bool{"item_url": true, "fallback_url": true}
+
+// applyAll applies UTM parameters to all link fields in JSON decoded message v
+func (b UTMBuilder) applyAll(v interface{}) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		isWebURL := v["type"] == string(ButtonTypeWebURL) // URL button or default action
+		for k, val := range v {
+			if s, ok := val.(string); ok && (urlFields[k] || k == "url" && isWebURL) && s != "" {
+				u, err := b.Apply(s)
+				if err != nil {
+					return err
+				}
+				v[k] = u
+				continue
+			}
+			if err := b.applyAll(val); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, val := range v {
+			if err := b.applyAll(val); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package messenger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// There is no message type for sending media attachments yet, so UTM parameters are applied
+// to encoded image attachment directly
+func TestAnalyticsSkipsMediaURL(t *testing.T) {
+	body := `{"recipient":{"id":"42"},"message":{"attachment":{"type":"image","payload":{"url":"http://mysite.com/photo.jpeg","is_reusable":true}}}}`
+	s, err := encodeMessage(rawTestMessage(body), []SendOption{WithAnalytics(AnalyticsSendConfig{CampaignID: "c42"})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(s), `"url":"http://mysite.com/photo.jpeg"`) {
+		t.Error("Expected media URL unchanged, sent", string(s))
+	}
+}
+
+type rawTestMessage string
+
+func (m rawTestMessage) foo() {}
+
+func (m rawTestMessage) MarshalJSON() ([]byte, error) { return json.RawMessage(m), nil }
@@ -0,0 +1,60 @@
+package messenger_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestUTMBuilder(t *testing.T) {
+	b := messenger.UTMBuilder{Campaign: "spring sale"}
+
+	tests := []struct {
+		url, expected string
+	}{
+		{"http://mysite.com", "http://mysite.com?utm_campaign=spring+sale&utm_medium=bot&utm_source=messenger"},
+		{"http://mysite.com/p?id=1&b=a%20b", "http://mysite.com/p?id=1&b=a%20b&utm_campaign=spring+sale&utm_medium=bot&utm_source=messenger"},
+		{"http://mysite.com/?utm_source=email", "http://mysite.com/?utm_source=email&utm_campaign=spring+sale&utm_medium=bot"},
+	}
+	for _, tt := range tests {
+		u, err := b.Apply(tt.url)
+		if err != nil || u != tt.expected {
+			t.Error("Expected", tt.expected, "returned", u, err)
+		}
+	}
+}
+
+func TestWithAnalytics(t *testing.T) {
+	t.Parallel()
+	var body string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"recipient_id":"12123213123","message_id":"mid.1"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	gm := msng.NewGenericMessage(12123213123)
+	gm.AddNewElement("Title", "", "http://mysite.com/?a=1", "http://mysite.com/photo.jpeg", []messenger.Button{msng.NewWebURLButton("Buy", "http://mysite.com/buy")})
+	el := msng.NewElement("Card", "", "", "", nil)
+	action := messenger.NewURLDefaultAction("http://mysite.com/card")
+	el.DefaultAction = &action
+	gm.AddElement(el)
+
+	if _, err := msng.SendMessage(gm, messenger.WithAnalytics(messenger.AnalyticsSendConfig{CampaignID: "c42"})); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`"item_url":"http://mysite.com/?a=1\u0026utm_campaign=c42\u0026utm_medium=bot\u0026utm_source=messenger"`,
+		`"url":"http://mysite.com/buy?utm_campaign=c42\u0026utm_medium=bot\u0026utm_source=messenger"`,
+		`"url":"http://mysite.com/card?utm_campaign=c42\u0026utm_medium=bot\u0026utm_source=messenger"`,
+		`"image_url":"http://mysite.com/photo.jpeg"`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Error("Expected", expected, "in", body)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package messenger
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WebhookConfig is app level webhook configuration, see ConfigureWebhook
+type WebhookConfig struct {
+	CallbackURL      string
+	VerifyToken      string
+	SubscribedFields []string // webhook fields like WebhookFieldMessages
+}
+
+// WebhookSubscription is app level webhook subscription
+type WebhookSubscription struct {
+	Object      string             `json:"object"`
+	CallbackURL string             `json:"callback_url"`
+	Active      bool               `json:"active"`
+	Fields      []WebhookFieldInfo `json:"fields"`
+}
+
+// WebhookFieldInfo is webhook field app is subscribed to, with Graph API version of its events
+type WebhookFieldInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// AppAccessToken returns app access token made of AppID and AppSecret, or "" if they are not set
+func (msng *Messenger) AppAccessToken() string {
+	if msng.AppID == "" || msng.AppSecret == "" {
+		return ""
+	}
+	return msng.AppID + "|" + msng.AppSecret
+}
+
+// ConfigureWebhook sets app's webhook for page events, requires AppID and AppSecret.
+// Facebook verifies callback URL with verify token, so webhook must be running already
+func (msng *Messenger) ConfigureWebhook(ctx context.Context, cfg WebhookConfig) error {
+	q := url.Values{
+		"object":       {"page"},
+		"callback_url": {cfg.CallbackURL},
+		"verify_token": {cfg.VerifyToken},
+		"fields":       {strings.Join(cfg.SubscribedFields, ",")},
+	}
+	return msng.appRequest(ctx, http.MethodPost, q, nil)
+}
+
+// DeleteWebhook removes app's webhook for page events, requires AppID and AppSecret
+func (msng *Messenger) DeleteWebhook(ctx context.Context) error {
+	return msng.appRequest(ctx, http.MethodDelete, url.Values{"object": {"page"}}, nil)
+}
+
+// GetWebhookSubscriptions returns app's webhook subscriptions, requires AppID and AppSecret
+func (msng *Messenger) GetWebhookSubscriptions(ctx context.Context) ([]WebhookSubscription, error) {
+	var reply struct {
+		Data []WebhookSubscription `json:"data"`
+	}
+	err := msng.appRequest(ctx, http.MethodGet, nil, &reply)
+	return reply.Data, err
+}
+
+// appRequest calls app's subscriptions endpoint with app access token
+func (msng *Messenger) appRequest(ctx context.Context, method string, query url.Values, v interface{}) error {
+	token := msng.AppAccessToken()
+	if token == "" {
+		return ErrNoAppCredentials
+	}
+	u := msng.graphURL(url.PathEscape(msng.AppID)+"/subscriptions", query, token)
+	return doGraphRequest(ctx, msng.GetClient(), method, u, nil, v)
+}
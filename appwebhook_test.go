@@ -0,0 +1,55 @@
+package messenger_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestConfigureWebhook(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/APP_ID/subscriptions" || r.FormValue("access_token") != "APP_ID|SECRET" {
+			t.Error("Unexpected request", r.URL)
+		}
+		switch r.Method {
+		case http.MethodPost:
+			if r.FormValue("object") != "page" || r.FormValue("callback_url") != "https://example.com/bot" ||
+				r.FormValue("verify_token") != verifyToken || r.FormValue("fields") != "messages,messaging_postbacks" {
+				t.Error("Unexpected webhook config", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"success":true}`))
+		case http.MethodGet:
+			w.Write([]byte(`{"data":[{"object":"page","callback_url":"https://example.com/bot","active":true,"fields":[{"name":"messages","version":"v2.6"}]}]}`))
+		default:
+			w.Write([]byte(`{"success":true}`))
+		}
+	})
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "", mock)
+	if err := msng.DeleteWebhook(ctx); err != messenger.ErrNoAppCredentials {
+		t.Error("Expected ErrNoAppCredentials, returned", err)
+	}
+
+	msng.AppID, msng.AppSecret = "APP_ID", "SECRET"
+	err := msng.ConfigureWebhook(ctx, messenger.WebhookConfig{
+		CallbackURL:      "https://example.com/bot",
+		VerifyToken:      verifyToken,
+		SubscribedFields: []string{messenger.WebhookFieldMessages, messenger.WebhookFieldMessagingPostbacks},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subs, err := msng.GetWebhookSubscriptions(ctx)
+	if err != nil || len(subs) != 1 || !subs[0].Active || subs[0].Fields[0].Name != "messages" {
+		t.Error("Unexpected subscriptions", subs, err)
+	}
+
+	if err := msng.DeleteWebhook(ctx); err != nil {
+		t.Error(err)
+	}
+}
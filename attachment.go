@@ -0,0 +1,145 @@
+package messenger
+
+import "encoding/json"
+
+// PayloadInterface is payload of attachment received in message, use type switch to get concrete payload
+type PayloadInterface interface {
+	payloadType() string
+}
+
+// ImagePayload is payload of image attachment
+type ImagePayload struct {
+	URL string `json:"url"`
+}
+
+// StickerPayload is payload of sticker, received as image attachment with sticker ID
+type StickerPayload struct {
+	URL       string `json:"url"`
+	StickerID int64  `json:"sticker_id"`
+}
+
+// VideoPayload is payload of video attachment
+type VideoPayload struct {
+	URL string `json:"url"`
+}
+
+// AudioPayload is payload of audio attachment
+type AudioPayload struct {
+	URL string `json:"url"`
+}
+
+// FilePayload is payload of file attachment
+type FilePayload struct {
+	URL string `json:"url"`
+}
+
+// LocationPayload is payload of location shared by user
+type LocationPayload struct {
+	Coordinates struct {
+		Lat  float64 `json:"lat"`
+		Long float64 `json:"long"`
+	} `json:"coordinates"`
+}
+
+// FallbackPayload is payload of attachment not supported by Messenger Platform, e.g. shared link
+type FallbackPayload struct {
+	Title string `json:"title,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+func (ImagePayload) payloadType() string    { return "image" }
+func (StickerPayload) payloadType() string  { return "image" }
+func (VideoPayload) payloadType() string    { return "video" }
+func (AudioPayload) payloadType() string    { return "audio" }
+func (FilePayload) payloadType() string     { return "file" }
+func (LocationPayload) payloadType() string { return "location" }
+func (FallbackPayload) payloadType() string { return "fallback" }
+
+// Attachment received in message, Payload returns payload of type matching attachment Type
+type Attachment struct {
+	Type    string
+	payload PayloadInterface
+}
+
+// NewAttachment creates attachment with payload p
+func NewAttachment(p PayloadInterface) Attachment {
+	return Attachment{Type: p.payloadType(), payload: p}
+}
+
+// Payload returns attachment payload, nil for unknown attachment types
+func (a Attachment) Payload() PayloadInterface {
+	return a.payload
+}
+
+type rawAttachment struct {
+	Type    string          `json:"type"`
+	Title   string          `json:"title,omitempty"` // fallback only
+	URL     string          `json:"url,omitempty"`   // fallback only
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// UnmarshalJSON decodes attachment payload into payload type matching attachment type
+func (a *Attachment) UnmarshalJSON(b []byte) error {
+	var raw rawAttachment
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	a.Type = raw.Type
+
+	var err error
+	switch raw.Type {
+	case "image":
+		var sticker StickerPayload
+		if err := unmarshalPayload(raw.Payload, &sticker); err != nil {
+			return err
+		}
+		if sticker.StickerID != 0 {
+			a.payload = sticker
+			return nil
+		}
+		a.payload, err = decodePayload[ImagePayload](raw.Payload)
+	case "video":
+		a.payload, err = decodePayload[VideoPayload](raw.Payload)
+	case "audio":
+		a.payload, err = decodePayload[AudioPayload](raw.Payload)
+	case "file":
+		a.payload, err = decodePayload[FilePayload](raw.Payload)
+	case "location":
+		a.payload, err = decodePayload[LocationPayload](raw.Payload)
+	case "fallback":
+		a.payload = FallbackPayload{Title: raw.Title, URL: raw.URL}
+	default:
+		a.payload = nil
+	}
+	return err
+}
+
+// MarshalJSON encodes attachment in the format it is received in
+func (a Attachment) MarshalJSON() ([]byte, error) {
+	raw := rawAttachment{Type: a.Type}
+	if f, ok := a.payload.(FallbackPayload); ok {
+		raw.Title, raw.URL = f.Title, f.URL
+		return json.Marshal(raw)
+	}
+	if a.payload != nil {
+		b, err := json.Marshal(a.payload)
+		if err != nil {
+			return nil, err
+		}
+		raw.Payload = b
+	}
+	return json.Marshal(raw)
+}
+
+func decodePayload[T PayloadInterface](b json.RawMessage) (PayloadInterface, error) {
+	var p T
+	err := unmarshalPayload(b, &p)
+	return p, err
+}
+
+func unmarshalPayload(b json.RawMessage, p interface{}) error {
+	if len(b) == 0 || string(b) == "null" {
+		return nil
+	}
+	return json.Unmarshal(b, p)
+}
@@ -0,0 +1,65 @@
+package messenger_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestAttachmentPayloads(t *testing.T) {
+	event := `{"object":"page","entry":[{"id":1,"time":1,"messaging":[{"sender":{"id":"7"},"recipient":{"id":"1"},"message":{"mid":"m1","attachments":[
+		{"type":"image","payload":{"url":"https://example.com/i.jpg"}},
+		{"type":"image","payload":{"url":"https://example.com/s.png","sticker_id":369239263222822}},
+		{"type":"video","payload":{"url":"https://example.com/v.mp4"}},
+		{"type":"audio","payload":{"url":"https://example.com/a.mp3"}},
+		{"type":"file","payload":{"url":"https://example.com/f.pdf"}},
+		{"type":"location","payload":{"coordinates":{"lat":44.8,"long":20.4}}},
+		{"type":"fallback","title":"Example","url":"https://example.com","payload":null}
+	]}}]}]}`
+	fbRq, err := messenger.DecodeRequest(httptest.NewRequest("POST", "/", strings.NewReader(event)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	attachments := fbRq.Entry[0].Messaging[0].Message.Attachments
+	if len(attachments) != 7 {
+		t.Fatal("Expected 7 attachments, decoded", len(attachments))
+	}
+
+	if p, ok := attachments[0].Payload().(messenger.ImagePayload); !ok || p.URL != "https://example.com/i.jpg" {
+		t.Error("Unexpected image payload", attachments[0].Payload())
+	}
+	if p, ok := attachments[1].Payload().(messenger.StickerPayload); !ok || p.StickerID != 369239263222822 {
+		t.Error("Unexpected sticker payload", attachments[1].Payload())
+	}
+	if _, ok := attachments[2].Payload().(messenger.VideoPayload); !ok {
+		t.Error("Unexpected video payload", attachments[2].Payload())
+	}
+	if _, ok := attachments[3].Payload().(messenger.AudioPayload); !ok {
+		t.Error("Unexpected audio payload", attachments[3].Payload())
+	}
+	if _, ok := attachments[4].Payload().(messenger.FilePayload); !ok {
+		t.Error("Unexpected file payload", attachments[4].Payload())
+	}
+	if p, ok := attachments[5].Payload().(messenger.LocationPayload); !ok || p.Coordinates.Lat != 44.8 || p.Coordinates.Long != 20.4 {
+		t.Error("Unexpected location payload", attachments[5].Payload())
+	}
+	if p, ok := attachments[6].Payload().(messenger.FallbackPayload); !ok || p.Title != "Example" || p.URL != "https://example.com" {
+		t.Error("Unexpected fallback payload", attachments[6].Payload())
+	}
+
+	b, err := json.Marshal(attachments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []messenger.Attachment
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, attachments) {
+		t.Error("Attachments changed in round trip", string(b))
+	}
+}
@@ -0,0 +1,61 @@
+package messenger
+
+import "context"
+
+// WithMaxConcurrentHandlers limits number of event handlers running at the same time to n
+func WithMaxConcurrentHandlers(n int) Option {
+	return func(msng *Messenger) {
+		msng.MaxConcurrentHandlers = n
+		msng.handlerSem = make(chan struct{}, n)
+	}
+}
+
+// initHandlerSemaphore creates semaphore if MaxConcurrentHandlers is set directly instead of with option
+func (msng *Messenger) initHandlerSemaphore() {
+	if msng.MaxConcurrentHandlers <= 0 {
+		return
+	}
+	cacheMu.Lock()
+	if msng.handlerSem == nil {
+		msng.handlerSem = make(chan struct{}, msng.MaxConcurrentHandlers)
+	}
+	cacheMu.Unlock()
+}
+
+// goHandler runs handler in new goroutine and reports if it was started. If MaxConcurrentHandlers is set,
+// it waits for free slot until ctx is done, event is dropped if ctx is done first
+func (msng *Messenger) goHandler(ctx context.Context, eventType EventType, handler func()) bool {
+	if msng.handlerSem == nil {
+		go handler()
+		return true
+	}
+
+	select {
+	case msng.handlerSem <- struct{}{}:
+	case <-ctx.Done():
+		msng.logger().Error("event dropped, too many running handlers", "event", string(eventType), "error", ctx.Err())
+		return false
+	}
+	go func() {
+		defer func() { <-msng.handlerSem }()
+		handler()
+	}()
+	return true
+}
+
+// waitTurn waits until t holds user lock. Handler slot is released while waiting,
+// so handlers waiting for busy user don't block handlers of other users
+func (msng *Messenger) waitTurn(t *lockTurn) {
+	select {
+	case <-t.ready:
+		return
+	default:
+	}
+	if msng.handlerSem == nil {
+		<-t.ready
+		return
+	}
+	<-msng.handlerSem
+	<-t.ready
+	msng.handlerSem <- struct{}{}
+}
@@ -0,0 +1,44 @@
+package messenger_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestMaxConcurrentHandlers(t *testing.T) {
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+	msng := messenger.New("XXXXXXX", "1", messenger.WithMaxConcurrentHandlers(2))
+	msng.MessageReceived = func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) {
+		defer wg.Done()
+		n := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	}
+
+	var events []string
+	for i := 0; i < 10; i++ {
+		events = append(events, fmt.Sprintf(`{"sender":{"id":"%d"},"recipient":{"id":"1"},"timestamp":1,"message":{"mid":"m%d","text":"hi"}}`, i+1, i))
+	}
+	wg.Add(len(events))
+	body := `{"object":"page","entry":[{"id":1,"time":1,"messaging":[` + strings.Join(events, ",") + `]}]}`
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	wg.Wait()
+
+	if maxRunning > 2 {
+		t.Error("Expected at most 2 concurrent handlers, max", maxRunning)
+	}
+}
@@ -0,0 +1,124 @@
+package messenger
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultOptOutKeywords are messages that opt user out of messages when BlockList is set
+var DefaultOptOutKeywords = []string{"STOP"}
+
+// BlockList stores users who opted out of messages. Messages are not sent to blocked users,
+// SendMessage returns ErrUserOptedOut instead
+type BlockList interface {
+	Block(userID string) error
+	Unblock(userID string) error
+	IsBlocked(userID string) (bool, error)
+}
+
+// WithBlockList sets block list checked before sending every message.
+// Users who send one of OptOutKeywords are added to the block list
+func WithBlockList(b BlockList) Option {
+	return func(msng *Messenger) {
+		msng.BlockList = b
+	}
+}
+
+// WithOptOutKeywords sets messages that opt user out, DefaultOptOutKeywords are used if not set.
+// Keywords are matched case insensitive
+func WithOptOutKeywords(keywords []string) Option {
+	return func(msng *Messenger) {
+		msng.OptOutKeywords = keywords
+	}
+}
+
+// MemoryBlockList is BlockList kept in memory
+type MemoryBlockList struct {
+	blocked sync.Map
+}
+
+// NewMemoryBlockList creates empty MemoryBlockList
+func NewMemoryBlockList() *MemoryBlockList {
+	return &MemoryBlockList{}
+}
+
+// Block adds user to block list
+func (b *MemoryBlockList) Block(userID string) error {
+	b.blocked.Store(userID, true)
+	return nil
+}
+
+// Unblock removes user from block list
+func (b *MemoryBlockList) Unblock(userID string) error {
+	b.blocked.Delete(userID)
+	return nil
+}
+
+// IsBlocked reports if user is in block list
+func (b *MemoryBlockList) IsBlocked(userID string) (bool, error) {
+	_, ok := b.blocked.Load(userID)
+	return ok, nil
+}
+
+// checkBlocked returns ErrUserOptedOut if recipient of JSON encoded message is blocked
+func (msng *Messenger) checkBlocked(s []byte) error {
+	if msng.BlockList == nil {
+		return nil
+	}
+	var f struct {
+		Recipient recipient `json:"recipient"`
+	}
+	if err := json.Unmarshal(s, &f); err != nil {
+		return err
+	}
+	if f.Recipient.ID == "" {
+		return nil // recipient identified by phone number, user ref...
+	}
+	return msng.checkUserBlocked(f.Recipient.ID)
+}
+
+// checkUserBlocked returns ErrUserOptedOut if user with userID is blocked
+func (msng *Messenger) checkUserBlocked(userID string) error {
+	if msng.BlockList == nil {
+		return nil
+	}
+	blocked, err := msng.BlockList.IsBlocked(userID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrUserOptedOut
+	}
+	return nil
+}
+
+// isOptOut reports if received text is opt out keyword
+func (msng *Messenger) isOptOut(text string) bool {
+	if msng.BlockList == nil {
+		return false
+	}
+	keywords := msng.OptOutKeywords
+	if keywords == nil {
+		keywords = DefaultOptOutKeywords
+	}
+	text = strings.TrimSpace(text)
+	for _, k := range keywords {
+		if strings.EqualFold(text, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// optOut adds user to block list and fires OptOutReceived
+func (msng *Messenger) optOut(userID int64) {
+	if err := msng.BlockList.Block(strconv.FormatInt(userID, 10)); err != nil {
+		msng.logger().Error("opt out failed", "user_id", userID, "error", err)
+		return
+	}
+	if msng.OptOutReceived != nil {
+		msng.OptOutReceived(msng, userID)
+	}
+}
@@ -0,0 +1,46 @@
+package messenger_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestBlockList(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"recipient_id":"12123213123","message_id":"mid.1"}`))
+	})
+
+	blocked := messenger.NewMemoryBlockList()
+	msng := messenger.New("XXXXXXX", "", mock, messenger.WithBlockList(blocked), messenger.WithOptOutKeywords([]string{"stop", "unsubscribe"}))
+	optOut := make(chan int64, 1)
+	msng.OptOutReceived = func(msng *messenger.Messenger, userID int64) {
+		optOut <- userID
+	}
+
+	event := `{"object":"page","entry":[{"id":1,"time":1458692752478,"messaging":[` +
+		`{"sender":{"id":"12123213123"},"recipient":{"id":"1"},"timestamp":1458692752478,"message":{"mid":"mid.1","text":" Unsubscribe "}}]}]}`
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(event)))
+	if userID := <-optOut; userID != 12123213123 {
+		t.Error("Unexpected opted out user", userID)
+	}
+
+	if _, err := msng.SendTextMessageStr(context.Background(), "12123213123", "hello"); err != messenger.ErrUserOptedOut {
+		t.Error("Expected ErrUserOptedOut, returned", err)
+	}
+	if calls != 0 {
+		t.Error("Message to blocked user sent to Facebook")
+	}
+
+	blocked.Unblock("12123213123")
+	if _, err := msng.SendTextMessageStr(context.Background(), "12123213123", "hello"); err != nil {
+		t.Error(err)
+	}
+}
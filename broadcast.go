@@ -0,0 +1,202 @@
+package messenger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultBroadcastWorkers is number of concurrent senders used when BroadcastOptions.ConcurrentWorkers is not set
+const defaultBroadcastWorkers = 10
+
+// BroadcastOptions configures BroadcastToUsers and MultiSend
+type BroadcastOptions struct {
+	ConcurrentWorkers int     // number of messages sent concurrently, 10 if not set
+	RPS               float64 // max messages sent per second, unlimited if not set
+	// OnError is called for every user message couldn't be sent to, it may be called concurrently
+	OnError func(userID string, err error)
+}
+
+// BroadcastError is error of sending broadcast message to single user
+type BroadcastError struct {
+	UserID string
+	Err    error
+}
+
+// Error implements error interface
+func (err BroadcastError) Error() string {
+	return fmt.Sprintf("messenger: broadcast to %s: %v", err.UserID, err.Err)
+}
+
+// Unwrap returns error returned by send
+func (err BroadcastError) Unwrap() error {
+	return err.Err
+}
+
+// BroadcastResult is summary of BroadcastToUsers and MultiSend
+type BroadcastResult struct {
+	Sent   int
+	Failed int
+	Errors []BroadcastError
+}
+
+// BroadcastSend is result of sending broadcast message to single user, Err is nil if message is sent
+type BroadcastSend struct {
+	UserID   string
+	Response FacebookResponse
+	Err      error
+}
+
+// PersonalizedSend is message for single user sent with MultiSend, MessageFn creates the message when it is sent
+type PersonalizedSend struct {
+	UserID    string
+	MessageFn func(userID string) (Message, error)
+}
+
+// BroadcastToUsers sends message m to every user in userIDs and waits until all messages are sent.
+// Recipient of m is replaced with each user, failing users don't stop the broadcast
+func (msng *Messenger) BroadcastToUsers(ctx context.Context, userIDs []string, m Message, opts BroadcastOptions) BroadcastResult {
+	return collectBroadcast(msng.BroadcastToUsersCh(ctx, userIDs, m, opts))
+}
+
+// BroadcastToUsersCh sends message m to every user in userIDs and returns channel with result for every user
+// as messages are sent. Channel is closed when broadcast is done. If ctx is cancelled,
+// users message is not sent to yet are reported with ctx error
+func (msng *Messenger) BroadcastToUsersCh(ctx context.Context, userIDs []string, m Message, opts BroadcastOptions) <-chan BroadcastSend {
+	sends := make([]PersonalizedSend, len(userIDs))
+	for i, userID := range userIDs {
+		sends[i] = PersonalizedSend{UserID: userID, MessageFn: func(string) (Message, error) { return m, nil }}
+	}
+	return msng.broadcast(ctx, sends, opts)
+}
+
+// SendResult is result of sending message to single user, received from SendResultStream
+type SendResult = BroadcastSend
+
+// SendResultStream streams results of broadcast as messages are sent
+type SendResultStream struct {
+	results <-chan SendResult
+}
+
+// BroadcastToUsersStream sends message m to every user in userIDs like BroadcastToUsers,
+// but returns immediately with stream of results. Results must be consumed with Wait, ForEach or Results
+func (msng *Messenger) BroadcastToUsersStream(ctx context.Context, userIDs []string, m Message, opts BroadcastOptions) *SendResultStream {
+	return &SendResultStream{results: msng.BroadcastToUsersCh(ctx, userIDs, m, opts)}
+}
+
+// Results returns channel with result for every user, it is closed when broadcast is done
+func (s *SendResultStream) Results() <-chan SendResult {
+	return s.results
+}
+
+// Wait waits until broadcast is done and returns its summary
+func (s *SendResultStream) Wait() BroadcastResult {
+	return collectBroadcast(s.results)
+}
+
+// ForEach calls fn for every result as message is sent, it returns when broadcast is done
+func (s *SendResultStream) ForEach(fn func(SendResult)) {
+	for r := range s.results {
+		fn(r)
+	}
+}
+
+// MultiSend sends personalized message to every user in sends and waits until all messages are sent.
+// MessageFn calls and sends run concurrently with the same limits as BroadcastToUsers, users whose
+// MessageFn fails are reported in result errors and don't stop sending to others
+func (msng *Messenger) MultiSend(ctx context.Context, sends []PersonalizedSend, opts BroadcastOptions) BroadcastResult {
+	return collectBroadcast(msng.broadcast(ctx, sends, opts))
+}
+
+// collectBroadcast waits for all broadcast results and summarizes them
+func collectBroadcast(results <-chan BroadcastSend) BroadcastResult {
+	var res BroadcastResult
+	for s := range results {
+		if s.Err != nil {
+			res.Failed++
+			res.Errors = append(res.Errors, BroadcastError{UserID: s.UserID, Err: s.Err})
+			continue
+		}
+		res.Sent++
+	}
+	return res
+}
+
+// broadcast sends messages to users in sends with opts.ConcurrentWorkers workers and returns channel with results
+func (msng *Messenger) broadcast(ctx context.Context, sends []PersonalizedSend, opts BroadcastOptions) <-chan BroadcastSend {
+	workers := opts.ConcurrentWorkers
+	if workers <= 0 {
+		workers = defaultBroadcastWorkers
+	}
+
+	jobs := make(chan PersonalizedSend)
+	results := make(chan BroadcastSend)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for s := range jobs {
+				var resp FacebookResponse
+				m, err := s.MessageFn(s.UserID)
+				if err == nil {
+					resp, err = msng.sendMessage(ctx, m, []SendOption{withRecipient(s.UserID)})
+				}
+				if err != nil && opts.OnError != nil {
+					opts.OnError(s.UserID, err)
+				}
+				results <- BroadcastSend{UserID: s.UserID, Response: resp, Err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer func() {
+			close(jobs)
+			wg.Wait()
+			close(results)
+		}()
+
+		var tick <-chan time.Time
+		if opts.RPS > 0 {
+			interval := time.Duration(float64(time.Second) / opts.RPS)
+			if interval <= 0 { // RPS above 1e9
+				interval = time.Nanosecond
+			}
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for i, s := range sends {
+			if i > 0 && tick != nil {
+				select {
+				case <-tick:
+				case <-ctx.Done():
+				}
+			}
+			if ctx.Err() == nil {
+				select {
+				case jobs <- s:
+					continue
+				case <-ctx.Done():
+				}
+			}
+			cancelBroadcast(ctx, sends[i:], opts, results)
+			return
+		}
+	}()
+	return results
+}
+
+// cancelBroadcast reports users in sends as failed with ctx error
+func cancelBroadcast(ctx context.Context, sends []PersonalizedSend, opts BroadcastOptions, results chan<- BroadcastSend) {
+	for _, s := range sends {
+		if opts.OnError != nil {
+			opts.OnError(s.UserID, ctx.Err())
+		}
+		results <- BroadcastSend{UserID: s.UserID, Err: ctx.Err()}
+	}
+}
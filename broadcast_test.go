@@ -0,0 +1,153 @@
+package messenger_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestBroadcastToUsers(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	received := map[string]bool{}
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Recipient struct{ ID string }
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		received[body.Recipient.ID] = true
+		mu.Unlock()
+		if body.Recipient.ID == "3" {
+			w.Write([]byte(`{"error":{"message":"This person isn't available right now.","type":"OAuthException","code":551}}`))
+			return
+		}
+		w.Write([]byte(`{"recipient_id":"` + body.Recipient.ID + `","message_id":"mid"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	m := msng.NewTextMessage(0, "Flash sale!")
+	var onError []string
+	res := msng.BroadcastToUsers(context.Background(), []string{"1", "2", "3", "4", "5"}, &m, messenger.BroadcastOptions{
+		ConcurrentWorkers: 2,
+		RPS:               100,
+		OnError: func(userID string, err error) {
+			mu.Lock()
+			onError = append(onError, userID)
+			mu.Unlock()
+		},
+	})
+
+	if res.Sent != 4 || res.Failed != 1 || len(res.Errors) != 1 {
+		t.Fatal("Unexpected result", res)
+	}
+	if res.Errors[0].UserID != "3" || !errors.Is(res.Errors[0], messenger.ErrUserBlocked) {
+		t.Error("Unexpected error", res.Errors[0])
+	}
+	if len(onError) != 1 || onError[0] != "3" {
+		t.Error("Expected OnError for user 3, called for", onError)
+	}
+	if len(received) != 5 {
+		t.Error("Expected message sent to all users, sent to", received)
+	}
+}
+
+func TestBroadcastToUsersCancelled(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"recipient_id":"1","message_id":"mid"}`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	msng := messenger.New("XXXXXXX", "", mock)
+	m := msng.NewTextMessage(0, "Flash sale!")
+	res := msng.BroadcastToUsers(ctx, []string{"1", "2", "3"}, &m, messenger.BroadcastOptions{})
+	if res.Sent != 0 || res.Failed != 3 || !errors.Is(res.Errors[0], context.Canceled) {
+		t.Error("Expected all users failed with context.Canceled, returned", res)
+	}
+}
+
+func TestMultiSend(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	received := map[string]string{}
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Recipient struct{ ID string }
+			Message   struct{ Text string }
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		received[body.Recipient.ID] = body.Message.Text
+		mu.Unlock()
+		w.Write([]byte(`{"recipient_id":"` + body.Recipient.ID + `","message_id":"mid"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	cart := map[string]int{"1": 3, "2": 1}
+	cartMessage := func(userID string) (messenger.Message, error) {
+		m := msng.NewTextMessage(0, fmt.Sprintf("You have %d items in your cart", cart[userID]))
+		return &m, nil
+	}
+	fnErr := errors.New("no cart")
+	res := msng.MultiSend(context.Background(), []messenger.PersonalizedSend{
+		{UserID: "1", MessageFn: cartMessage},
+		{UserID: "2", MessageFn: cartMessage},
+		{UserID: "3", MessageFn: func(string) (messenger.Message, error) { return nil, fnErr }},
+	}, messenger.BroadcastOptions{ConcurrentWorkers: 2})
+
+	if res.Sent != 2 || res.Failed != 1 || res.Errors[0].UserID != "3" || !errors.Is(res.Errors[0], fnErr) {
+		t.Fatal("Unexpected result", res)
+	}
+	if received["1"] != "You have 3 items in your cart" || received["2"] != "You have 1 items in your cart" || len(received) != 2 {
+		t.Error("Unexpected messages", received)
+	}
+}
+
+func TestBroadcastToUsersStream(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"recipient_id":"1","message_id":"mid"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	m := msng.NewTextMessage(0, "Flash sale!")
+	users := []string{"1", "2", "3"}
+
+	progress := 0
+	msng.BroadcastToUsersStream(context.Background(), users, &m, messenger.BroadcastOptions{}).ForEach(func(r messenger.SendResult) {
+		if r.Err != nil {
+			t.Error(r.UserID, r.Err)
+		}
+		progress++
+	})
+	if progress != len(users) {
+		t.Error("Expected result for every user, received", progress)
+	}
+
+	res := msng.BroadcastToUsersStream(context.Background(), users, &m, messenger.BroadcastOptions{}).Wait()
+	if res.Sent != 3 || res.Failed != 0 {
+		t.Error("Unexpected result", res)
+	}
+}
+
+func TestBroadcastToUsersHighRPS(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"recipient_id":"1","message_id":"mid"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	m := msng.NewTextMessage(0, "Flash sale!")
+	res := msng.BroadcastToUsers(context.Background(), []string{"1", "2"}, &m, messenger.BroadcastOptions{RPS: 1e12})
+	if res.Sent != 2 {
+		t.Error("Unexpected result", res)
+	}
+}
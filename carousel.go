@@ -0,0 +1,74 @@
+package messenger
+
+import (
+	"context"
+	"fmt"
+)
+
+// CarouselCard is card in carousel sent with SendCarousel
+type CarouselCard struct {
+	Title    string
+	Subtitle string
+	ImageURL string
+	WebURL   string   // optional, opened when user taps the card
+	Buttons  []Button // up to 3 buttons
+}
+
+// NewCarouselCard creates carousel card, webURL is optional
+func NewCarouselCard(title, subtitle, imageURL, webURL string) *CarouselCard {
+	return &CarouselCard{
+		Title:    title,
+		Subtitle: subtitle,
+		ImageURL: imageURL,
+		WebURL:   webURL,
+	}
+}
+
+// WithButton adds button b to card
+func (c *CarouselCard) WithButton(b Button) *CarouselCard {
+	c.Buttons = append(c.Buttons, b)
+	return c
+}
+
+// element converts card to generic template element
+func (c CarouselCard) element() (Element, error) {
+	if len(c.Buttons) > maxElementButtons {
+		return Element{}, fmt.Errorf("%w: card %q has %d buttons", ErrMaxButtons, c.Title, len(c.Buttons))
+	}
+	e := newElement(c.Title, c.Subtitle, "", c.ImageURL, c.Buttons)
+	if c.WebURL != "" {
+		if err := e.WithDefaultAction(NewURLDefaultAction(c.WebURL)); err != nil {
+			return Element{}, err
+		}
+	}
+	return e, e.validate()
+}
+
+// SendCarousel sends cards to recipientID as horizontally scrollable generic template message.
+// Carousel can have up to 10 cards
+func (msng *Messenger) SendCarousel(ctx context.Context, recipientID string, cards []CarouselCard) (FacebookResponse, error) {
+	if len(cards) == 0 {
+		return FacebookResponse{}, ErrEmptyCarousel
+	}
+	if len(cards) > maxElements {
+		return FacebookResponse{}, fmt.Errorf("%w: %d cards", ErrMaxElements, len(cards))
+	}
+
+	m := GenericMessage{
+		Recipient: newRecipient(recipientID),
+		Message: genericMessageContent{
+			Attachment: &attachment{
+				Type:    "template",
+				Payload: payload{TemplateType: "generic"},
+			},
+		},
+	}
+	for _, c := range cards {
+		e, err := c.element()
+		if err != nil {
+			return FacebookResponse{}, err
+		}
+		m.Message.Attachment.Payload.Elements = append(m.Message.Attachment.Payload.Elements, e)
+	}
+	return msng.sendMessage(ctx, &m, nil)
+}
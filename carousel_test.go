@@ -0,0 +1,62 @@
+package messenger_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestSendCarousel(t *testing.T) {
+	fb.Reset()
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "")
+	cards := []messenger.CarouselCard{
+		*messenger.NewCarouselCard("First", "One", "https://example.com/1.jpg", "https://example.com/1").
+			WithButton(msng.NewWebURLButton("Open", "https://example.com/1")),
+		*messenger.NewCarouselCard("Second", "Two", "https://example.com/2.jpg", ""),
+	}
+	if _, err := msng.SendCarousel(ctx, "42", cards); err != nil {
+		t.Fatal(err)
+	}
+
+	call, _ := fb.LastCall()
+	var body struct {
+		Recipient struct{ ID string }
+		Message   struct {
+			Attachment struct {
+				Payload struct {
+					TemplateType string `json:"template_type"`
+					Elements     []messenger.Element
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(call.Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	p := body.Message.Attachment.Payload
+	if body.Recipient.ID != "42" || p.TemplateType != "generic" || len(p.Elements) != 2 {
+		t.Fatal("Unexpected message", string(call.Body))
+	}
+	if p.Elements[0].DefaultAction == nil || p.Elements[0].DefaultAction.URL != "https://example.com/1" || len(p.Elements[0].Buttons) != 1 {
+		t.Error("Unexpected first card", p.Elements[0])
+	}
+	if p.Elements[1].DefaultAction != nil {
+		t.Error("Expected no default action without web URL", p.Elements[1].DefaultAction)
+	}
+
+	if _, err := msng.SendCarousel(ctx, "42", nil); !errors.Is(err, messenger.ErrEmptyCarousel) {
+		t.Error("Expected ErrEmptyCarousel, returned", err)
+	}
+	card := messenger.NewCarouselCard("Buttons", "", "", "")
+	for i := 0; i < 4; i++ {
+		card.WithButton(msng.NewPostbackButton("Tap", "TAP"))
+	}
+	if _, err := msng.SendCarousel(ctx, "42", []messenger.CarouselCard{*card}); !errors.Is(err, messenger.ErrMaxButtons) {
+		t.Error("Expected ErrMaxButtons, returned", err)
+	}
+}
@@ -0,0 +1,79 @@
+package messenger
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+const chatPluginSDKVersion = "v18.0"
+
+var (
+	pageIDRe     = regexp.MustCompile(`^[0-9]+$`)
+	themeColorRe = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+)
+
+// ChatPluginConfig is configuration of Customer Chat Plugin embedded in your website
+type ChatPluginConfig struct {
+	PageID            string
+	LoggedInGreeting  string
+	LoggedOutGreeting string
+	ThemeColor        string // hex color like #0084ff
+	GuestChat         bool   // allow chat without Facebook login, guest mode must be enabled in page settings
+	Ref               string // sent back to webhook in referral event
+}
+
+// Validate checks page ID and theme color
+func (cfg ChatPluginConfig) Validate() error {
+	if !pageIDRe.MatchString(cfg.PageID) {
+		return errors.New("messenger: invalid page ID " + cfg.PageID)
+	}
+	if cfg.ThemeColor != "" && !themeColorRe.MatchString(cfg.ThemeColor) {
+		return errors.New("messenger: invalid theme color " + cfg.ThemeColor)
+	}
+	return nil
+}
+
+// GenerateChatPluginSnippet returns HTML snippet with Customer Chat Plugin to be injected in your web page
+func GenerateChatPluginSnippet(cfg ChatPluginConfig) string {
+	attrs := [][2]string{
+		{"page_id", cfg.PageID},
+		{"attribution", "biz_inbox"},
+		{"theme_color", cfg.ThemeColor},
+		{"logged_in_greeting", cfg.LoggedInGreeting},
+		{"logged_out_greeting", cfg.LoggedOutGreeting},
+		{"ref", cfg.Ref},
+	}
+
+	var b strings.Builder
+	b.WriteString("<div id=\"fb-root\"></div>\n<div class=\"fb-customerchat\"")
+	for _, a := range attrs {
+		if a[1] != "" {
+			fmt.Fprintf(&b, " %s=\"%s\"", a[0], html.EscapeString(a[1]))
+		}
+	}
+	b.WriteString("></div>\n")
+
+	// guest mode is only supported by customer chat SDK
+	sdk := "https://connect.facebook.net/en_US/sdk.js"
+	if cfg.GuestChat {
+		sdk = "https://connect.facebook.net/en_US/sdk/xfbml.customerchat.js"
+	}
+	fmt.Fprintf(&b, `<script>
+  window.fbAsyncInit = function() {
+    FB.init({xfbml: true, version: '%s'});
+  };
+  (function(d, s, id) {
+    var js, fjs = d.getElementsByTagName(s)[0];
+    if (d.getElementById(id)) return;
+    js = d.createElement(s); js.id = id;
+    js.src = '%s';
+    fjs.parentNode.insertBefore(js, fjs);
+  }(document, 'script', 'facebook-jssdk'));
+</script>
+`, chatPluginSDKVersion, sdk)
+
+	return b.String()
+}
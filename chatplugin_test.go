@@ -0,0 +1,39 @@
+package messenger_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestChatPluginSnippet(t *testing.T) {
+	cfg := messenger.ChatPluginConfig{
+		PageID:           "123456789",
+		LoggedInGreeting: `Hi "there"`,
+		ThemeColor:       "#0084ff",
+		GuestChat:        true,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := messenger.GenerateChatPluginSnippet(cfg)
+	for _, expected := range []string{
+		`<div class="fb-customerchat" page_id="123456789" attribution="biz_inbox" theme_color="#0084ff" logged_in_greeting="Hi &#34;there&#34;"></div>`,
+		"xfbml.customerchat.js",
+	} {
+		if !strings.Contains(s, expected) {
+			t.Error("Snippet doesn't contain", expected, "\n", s)
+		}
+	}
+
+	cfg.ThemeColor = "blue"
+	if cfg.Validate() == nil {
+		t.Error("Expected invalid theme color")
+	}
+	cfg = messenger.ChatPluginConfig{PageID: "my-page"}
+	if cfg.Validate() == nil {
+		t.Error("Expected invalid page ID")
+	}
+}
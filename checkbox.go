@@ -0,0 +1,77 @@
+package messenger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// CheckboxPayload is data from signed request delivered by Checkbox Plugin
+type CheckboxPayload struct {
+	UserRef   string `json:"user_ref"`
+	Ref       string `json:"ref"`
+	PageID    string `json:"page_id"`
+	AppID     string `json:"app_id"`
+	Algorithm string `json:"algorithm"`
+	IssuedAt  int64  `json:"issued_at"`
+}
+
+// ParseCheckboxPayload verifies signature of Checkbox Plugin signed request with app secret and returns its payload.
+// ErrMissingAppSecret is returned if appSecret is empty
+func ParseCheckboxPayload(signedRequest string, appSecret string) (CheckboxPayload, error) {
+	if appSecret == "" {
+		return CheckboxPayload{}, ErrMissingAppSecret // anyone can sign request with empty key
+	}
+	parts := strings.SplitN(signedRequest, ".", 2)
+	if len(parts) != 2 {
+		return CheckboxPayload{}, ErrInvalidSignedRequest
+	}
+
+	sig, err := decodeBase64URL(parts[0])
+	if err != nil {
+		return CheckboxPayload{}, ErrInvalidSignedRequest
+	}
+
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	mac.Write([]byte(parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return CheckboxPayload{}, ErrInvalidSignedRequest
+	}
+
+	b, err := decodeBase64URL(parts[1])
+	if err != nil {
+		return CheckboxPayload{}, ErrInvalidSignedRequest
+	}
+
+	var p CheckboxPayload
+	if err := json.Unmarshal(b, &p); err != nil {
+		return CheckboxPayload{}, ErrInvalidSignedRequest
+	}
+	if !strings.EqualFold(p.Algorithm, "HMAC-SHA256") {
+		return CheckboxPayload{}, ErrInvalidSignedRequest
+	}
+	return p, nil
+}
+
+// ValidateCheckboxPluginToken checks that Checkbox Plugin signed request is signed with messenger's AppSecret
+// and issued for appID, userRef and ref (ref is not checked if empty). ErrMissingAppSecret is returned if AppSecret is not set
+func (msng *Messenger) ValidateCheckboxPluginToken(userRef, ref, appID string, signedRequest string) (bool, error) {
+	p, err := ParseCheckboxPayload(signedRequest, msng.AppSecret)
+	if err != nil {
+		return false, err
+	}
+	if p.AppID != appID || p.UserRef != userRef {
+		return false, nil
+	}
+	if ref != "" && p.Ref != ref {
+		return false, nil
+	}
+	return true, nil
+}
+
+// decodeBase64URL decodes base64url string with or without padding
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
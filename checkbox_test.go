@@ -0,0 +1,53 @@
+package messenger_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+const appSecret = "app_secret"
+
+func signRequest(secret, payload string) string {
+	p := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(p))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) + "." + p
+}
+
+func TestCheckboxPlugin(t *testing.T) {
+	sr := signRequest(appSecret, `{"algorithm":"HMAC-SHA256","issued_at":1500000000,"user_ref":"UNIQUE_REF","ref":"cart","page_id":"1","app_id":"42"}`)
+
+	p, err := messenger.ParseCheckboxPayload(sr, appSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.UserRef != "UNIQUE_REF" || p.PageID != "1" || p.IssuedAt != 1500000000 {
+		t.Error("Unexpected payload", p)
+	}
+
+	msng := &messenger.Messenger{AppSecret: appSecret}
+	if ok, err := msng.ValidateCheckboxPluginToken("UNIQUE_REF", "cart", "42", sr); !ok || err != nil {
+		t.Error("Expected valid token", err)
+	}
+	if ok, _ := msng.ValidateCheckboxPluginToken("UNIQUE_REF", "cart", "43", sr); ok {
+		t.Error("Expected app ID mismatch")
+	}
+
+	forged := signRequest("other_secret", `{"algorithm":"HMAC-SHA256","user_ref":"UNIQUE_REF","app_id":"42"}`)
+	if _, err := msng.ValidateCheckboxPluginToken("UNIQUE_REF", "", "42", forged); err != messenger.ErrInvalidSignedRequest {
+		t.Error("Expected ErrInvalidSignedRequest, returned", err)
+	}
+}
+
+func TestCheckboxPluginWithoutAppSecret(t *testing.T) {
+	forged := signRequest("", `{"algorithm":"HMAC-SHA256","user_ref":"UNIQUE_REF","app_id":"42"}`)
+
+	msng := &messenger.Messenger{}
+	if ok, err := msng.ValidateCheckboxPluginToken("UNIQUE_REF", "", "42", forged); ok || err != messenger.ErrMissingAppSecret {
+		t.Error("Expected ErrMissingAppSecret, returned", ok, err)
+	}
+}
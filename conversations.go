@@ -0,0 +1,132 @@
+package messenger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// conversationFields are fields of Conversation requested from Graph API
+const conversationFields = "id,link,updated_time,snippet,message_count,can_reply,participants"
+
+// Participant is user or page participating in conversation
+type Participant = ThreadParticipant
+
+// Conversation between page and user
+type Conversation struct {
+	ID           string        `json:"id"`
+	Link         string        `json:"link"`
+	UpdatedTime  time.Time     `json:"-"`
+	Snippet      string        `json:"snippet"`
+	MessageCount int           `json:"message_count"`
+	CanReply     bool          `json:"can_reply"`
+	Participants []Participant `json:"-"`
+}
+
+// UnmarshalJSON decodes conversation received from Graph API
+func (c *Conversation) UnmarshalJSON(b []byte) error {
+	type conversation Conversation
+	var raw struct {
+		conversation
+		UpdatedTime  string `json:"updated_time"`
+		Participants struct {
+			Data []Participant `json:"data"`
+		} `json:"participants"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*c = Conversation(raw.conversation)
+	c.Participants = raw.Participants.Data
+	if raw.UpdatedTime != "" {
+		updated, err := time.Parse(graphTimeLayout, raw.UpdatedTime)
+		if err != nil {
+			return err
+		}
+		c.UpdatedTime = updated
+	}
+	return nil
+}
+
+// ConversationMessage is message in conversation
+type ConversationMessage struct {
+	ID          string `json:"id"`
+	CreatedTime string `json:"created_time"`
+	Message     string `json:"message"`
+	From        struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"from"`
+}
+
+// ConversationPageResult is page of conversations returned by GetConversationsPage,
+// use Fetch to get the next page
+type ConversationPageResult struct {
+	Data       []Conversation
+	TotalCount int    // number of all page's conversations
+	NextCursor string // empty for the last page
+
+	msng *Messenger
+}
+
+// Fetch returns next page of conversations, ErrNoMorePages if this is the last page
+func (res *ConversationPageResult) Fetch(ctx context.Context) (*ConversationPageResult, error) {
+	if res.NextCursor == "" {
+		return nil, ErrNoMorePages
+	}
+	return res.msng.GetConversationsPage(ctx, res.NextCursor)
+}
+
+// GetConversations returns all page's Messenger conversations
+func (msng *Messenger) GetConversations(ctx context.Context) ([]Conversation, error) {
+	return fetchAllGraphPages[Conversation](ctx, msng, "me/conversations", conversationsQuery())
+}
+
+// IterateConversations returns iterator of page's Messenger conversations
+func (msng *Messenger) IterateConversations() *PageIterator[Conversation] {
+	return NewPageIterator(graphPageFetcher[Conversation](msng, "me/conversations", conversationsQuery()))
+}
+
+func conversationsQuery() url.Values {
+	return url.Values{
+		"platform": {"messenger"},
+		"fields":   {conversationFields},
+	}
+}
+
+// GetConversationsPage returns page of page's Messenger conversations, cursor is "" for the first page
+func (msng *Messenger) GetConversationsPage(ctx context.Context, cursor string) (*ConversationPageResult, error) {
+	q := url.Values{
+		"platform": {"messenger"},
+		"fields":   {conversationFields},
+		"summary":  {"total_count"},
+	}
+	if cursor != "" {
+		q.Set("after", cursor)
+	}
+
+	var resp struct {
+		PagedResponse[Conversation]
+		Summary struct {
+			TotalCount int `json:"total_count"`
+		} `json:"summary"`
+	}
+	if err := msng.graphRequest(ctx, http.MethodGet, "me/conversations", q, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &ConversationPageResult{
+		Data:       resp.Data,
+		TotalCount: resp.Summary.TotalCount,
+		NextCursor: resp.NextCursor(),
+		msng:       msng,
+	}, nil
+}
+
+// GetConversationMessages returns iterator of messages in conversation, newest first
+func (msng *Messenger) GetConversationMessages(conversationID string) *PageIterator[ConversationMessage] {
+	q := url.Values{"fields": {"id,created_time,message,from"}}
+	return NewPageIterator(graphPageFetcher[ConversationMessage](msng, url.PathEscape(conversationID)+"/messages", q))
+}
@@ -0,0 +1,77 @@
+package messenger_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestGetConversationsPage(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("summary") != "total_count" {
+			t.Error("Expected total count summary, requested", r.FormValue("summary"))
+		}
+		if r.FormValue("after") == "" {
+			w.Write([]byte(`{"data":[{"id":"t_1","updated_time":"2020-01-02T15:04:05+0000","snippet":"hi","message_count":3,"can_reply":true,
+				"participants":{"data":[{"id":"1254477777772919","name":"Jane Doe","email":"1254477777772919@facebook.com"},{"id":"1","name":"Pizza Bot"}]}}],
+				"paging":{"cursors":{"after":"AFTER"},"next":"https://graph.facebook.com/next"},"summary":{"total_count":2}}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"t_2","can_reply":false}],"paging":{"cursors":{"before":"BEFORE"}},"summary":{"total_count":2}}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "1", mock)
+	var all []messenger.Conversation
+	res, err := msng.GetConversationsPage(context.Background(), "")
+	for err == nil {
+		all = append(all, res.Data...)
+		if res.NextCursor == "" {
+			break
+		}
+		res, err = res.Fetch(context.Background())
+	}
+	if err != nil || len(all) != 2 || res.TotalCount != 2 {
+		t.Fatal("Unexpected conversations", all, err)
+	}
+	if c := all[0]; !c.CanReply || c.MessageCount != 3 || len(c.Participants) != 2 || c.Participants[0].Name != "Jane Doe" {
+		t.Error("Conversation not decoded", c)
+	}
+	if !all[0].UpdatedTime.Equal(time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)) {
+		t.Error("Unexpected updated time", all[0].UpdatedTime)
+	}
+	if all[1].CanReply {
+		t.Error("Expected conversation that can't be replied to", all[1])
+	}
+	if _, err := res.Fetch(context.Background()); err != messenger.ErrNoMorePages {
+		t.Error("Expected ErrNoMorePages, returned", err)
+	}
+}
+
+func TestGetConversations(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/conversations" || r.FormValue("platform") != "messenger" {
+			t.Error("Unexpected request", r.URL)
+		}
+		if r.FormValue("after") == "" {
+			w.Write([]byte(`{"data":[{"id":"t_1"}],"paging":{"cursors":{"after":"AFTER"},"next":"https://graph.facebook.com/next"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"t_2"}],"paging":{"cursors":{"before":"BEFORE"}}}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "1", mock)
+	all, err := msng.GetConversations(context.Background())
+	if err != nil || len(all) != 2 || all[1].ID != "t_2" {
+		t.Error("Unexpected conversations", all, err)
+	}
+
+	all, err = messenger.CollectAll(context.Background(), msng.IterateConversations())
+	if err != nil || len(all) != 2 || all[0].ID != "t_1" {
+		t.Error("Unexpected conversations", all, err)
+	}
+}
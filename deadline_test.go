@@ -0,0 +1,60 @@
+package messenger_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestWithRequestDeadline(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(300 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`{"recipient_id":"42","message_id":"mid"}`))
+	})
+
+	queue := messenger.NewMemoryRetryQueue()
+	msng := messenger.New("XXXXXXX", "", mock, messenger.WithRetryQueue(queue))
+	m := msng.NewTextMessage(42, "Your code is 1234")
+
+	start := time.Now()
+	_, err := msng.SendMessage(&m, messenger.WithRequestDeadline(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected context.DeadlineExceeded, returned", err)
+	}
+	if time.Since(start) > 200*time.Millisecond {
+		t.Error("Expected send to fail fast, took", time.Since(start))
+	}
+	if queue.Len() != 0 {
+		t.Error("Expected message with deadline not to be retried")
+	}
+}
+
+func TestWithCallTimeout(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(50 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`{"recipient_id":"42","message_id":"mid"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock, messenger.WithSendTimeout(30*time.Second))
+	m := msng.NewTextMessage(42, "Hello")
+	if _, err := msng.SendMessage(&m, messenger.WithCallTimeout(time.Millisecond)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected per call timeout to override send timeout, returned", err)
+	}
+
+	msng.SendTimeout = time.Millisecond
+	if _, err := msng.SendMessage(&m, messenger.WithCallTimeout(30*time.Second)); err != nil {
+		t.Error("Expected longer per call timeout to override send timeout, returned", err)
+	}
+}
@@ -0,0 +1,106 @@
+package messenger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"regexp"
+)
+
+// accessTokenRe matches access tokens in query strings and JSON bodies so they never end up in debug logs
+var accessTokenRe = regexp.MustCompile(`(access_token(?:=|"\s*:\s*"))[^&"\s]*`)
+
+// DebugTransport is http.RoundTripper that logs every request sent to Facebook and every response received.
+// Access tokens are redacted from logged URLs and bodies.
+// It can be layered with other transports, Base is used for the actual round trip (http.DefaultTransport if nil)
+type DebugTransport struct {
+	Base   http.RoundTripper
+	Writer io.Writer // os.Stderr if nil
+}
+
+// WithDebugTransport wraps messenger's HTTP transport with DebugTransport that logs to w
+func WithDebugTransport(w io.Writer) Option {
+	return func(msng *Messenger) {
+		c := *msng.GetClient() // copy, don't change client that might be shared with the rest of the app
+		c.Transport = &DebugTransport{Base: c.Transport, Writer: w}
+		msng.HttpClient = &c
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *DebugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	w := t.Writer
+	if w == nil {
+		w = os.Stderr
+	}
+
+	reqBody, req, err := peekRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(w, "--> %s %s\n", req.Method, redact(req.URL.String()))
+	req.Header.Write(w)
+	fmt.Fprintf(w, "\n%s\n", redact(string(reqBody)))
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		fmt.Fprintf(w, "<-- ERROR %v\n", err)
+		return resp, err
+	}
+
+	// read the body and put it back so the caller can still decode it
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		return resp, err
+	}
+
+	fmt.Fprintf(w, "<-- %s\n", resp.Status)
+	resp.Header.Write(w)
+	fmt.Fprintf(w, "\n%s\n", redact(string(respBody)))
+
+	return resp, nil
+}
+
+func (t *DebugTransport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// peekRequestBody returns request body without consuming the body that will be sent
+// if request can't recreate its body, a clone with buffered body is returned instead
+func peekRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, req, err
+		}
+		defer body.Close()
+		b, err := ioutil.ReadAll(body)
+		return b, req, err
+	}
+
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, req, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return b, clone, nil
+}
+
+func redact(s string) string {
+	return accessTokenRe.ReplaceAllString(s, "${1}REDACTED")
+}
@@ -0,0 +1,56 @@
+package messenger_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestWithDebugTransport(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"1","first_name":"John","last_name":"Doe"}`))
+	})
+
+	var buf bytes.Buffer
+	msng := messenger.New("SECRET_TOKEN", "", mock, messenger.WithDebugTransport(&buf))
+	p, err := msng.GetUserProfile(context.Background(), "1")
+	if err != nil || p.FirstName != "John" {
+		t.Fatal("Expected response body to be decoded after logging", p, err)
+	}
+
+	log := buf.String()
+	if strings.Contains(log, "SECRET_TOKEN") || !strings.Contains(log, "access_token=REDACTED") {
+		t.Error("Expected access token to be redacted", log)
+	}
+	if !strings.Contains(log, "--> GET ") || !strings.Contains(log, "<-- 200 OK") || !strings.Contains(log, `"first_name":"John"`) {
+		t.Error("Expected request and response to be logged", log)
+	}
+}
+
+func TestDebugTransportRequestBody(t *testing.T) {
+	t.Parallel()
+	var sent string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var b bytes.Buffer
+		b.ReadFrom(r.Body)
+		sent = b.String()
+		w.Write([]byte(`{"recipient_id":"1","message_id":"mid.1"}`))
+	})
+
+	var buf bytes.Buffer
+	msng := messenger.New("XXXXXXX", "", mock, messenger.WithDebugTransport(&buf))
+	if _, err := msng.SendTextMessageStr(context.Background(), "1", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sent, `"text":"hello"`) {
+		t.Error("Expected request body to be sent after logging, sent", sent)
+	}
+	if !strings.Contains(buf.String(), `"text":"hello"`) {
+		t.Error("Expected request body to be logged", buf.String())
+	}
+}
@@ -0,0 +1,130 @@
+package messenger
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DomainValidator checks if domain is whitelisted for the page, see WithDomainValidator
+type DomainValidator interface {
+	IsWhitelisted(domain string) bool
+}
+
+// WithDomainValidator sets validator used for checking domains of URLs opened with messenger extensions.
+// Messages with URLs on domains that are not whitelisted are not sent, ErrDomainNotWhitelisted is returned instead
+func WithDomainValidator(v DomainValidator) Option {
+	return func(msng *Messenger) {
+		msng.DomainValidator = v
+	}
+}
+
+// GetWhitelistedDomains returns domains whitelisted in messenger profile
+func (msng *Messenger) GetWhitelistedDomains(ctx context.Context) ([]string, error) {
+	var p struct {
+		WhitelistedDomains []string `json:"whitelisted_domains"`
+	}
+	err := msng.getMessengerProfile(ctx, []string{"whitelisted_domains"}, &p)
+	return p.WhitelistedDomains, err
+}
+
+// WhitelistedDomainValidator is DomainValidator that checks domains against domains whitelisted in messenger profile
+type WhitelistedDomainValidator struct {
+	mu      sync.RWMutex
+	domains map[string]bool
+}
+
+// NewWhitelistedDomainValidator creates validator seeded with whitelisted domains of msng's page.
+// If refresh is greater than zero, domains are reloaded every refresh interval until ctx is cancelled
+func NewWhitelistedDomainValidator(ctx context.Context, msng *Messenger, refresh time.Duration) (*WhitelistedDomainValidator, error) {
+	v := &WhitelistedDomainValidator{}
+	if err := v.load(ctx, msng); err != nil {
+		return nil, err
+	}
+	if refresh > 0 {
+		go func() {
+			ticker := time.NewTicker(refresh)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := v.load(ctx, msng); err != nil {
+						msng.logger().Error("whitelisted domains refresh failed", "error", err)
+					}
+				}
+			}
+		}()
+	}
+	return v, nil
+}
+
+func (v *WhitelistedDomainValidator) load(ctx context.Context, msng *Messenger) error {
+	whitelisted, err := msng.GetWhitelistedDomains(ctx)
+	if err != nil {
+		return err
+	}
+	domains := map[string]bool{}
+	for _, d := range whitelisted {
+		if u, err := url.Parse(d); err == nil && u.Host != "" {
+			d = u.Hostname()
+		}
+		domains[strings.ToLower(d)] = true
+	}
+
+	v.mu.Lock()
+	v.domains = domains
+	v.mu.Unlock()
+	return nil
+}
+
+// IsWhitelisted implements DomainValidator
+func (v *WhitelistedDomainValidator) IsWhitelisted(domain string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.domains[strings.ToLower(domain)]
+}
+
+// checkDomains checks domains of all URLs opened with messenger extensions in JSON encoded message
+func (msng *Messenger) checkDomains(s []byte) error {
+	if msng.DomainValidator == nil {
+		return nil
+	}
+	var body interface{}
+	if err := json.Unmarshal(s, &body); err != nil {
+		return err
+	}
+	return msng.checkDomainsOf(body)
+}
+
+func (msng *Messenger) checkDomainsOf(v interface{}) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if ext, _ := v["messenger_extensions"].(bool); ext {
+			s, _ := v["url"].(string)
+			u, err := url.Parse(s)
+			if err != nil {
+				return err
+			}
+			if !msng.DomainValidator.IsWhitelisted(u.Hostname()) {
+				return ErrDomainNotWhitelisted{Domain: u.Hostname()}
+			}
+		}
+		for _, val := range v {
+			if err := msng.checkDomainsOf(val); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, val := range v {
+			if err := msng.checkDomainsOf(val); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
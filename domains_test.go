@@ -0,0 +1,61 @@
+package messenger_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestDomainValidator(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/me/messenger_profile" {
+			w.Write([]byte(`{"data":[{"whitelisted_domains":["https://shop.example.com/"]}]}`))
+			return
+		}
+		w.Write([]byte(`{"recipient_id":"123","message_id":"mid.1"}`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	msng := messenger.New("XXXXXXX", "", mock)
+	v, err := messenger.NewWhitelistedDomainValidator(ctx, &msng, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.IsWhitelisted("shop.example.com") || v.IsWhitelisted("example.com") {
+		t.Error("Unexpected whitelisted domains")
+	}
+	msng.DomainValidator = v
+
+	gm := msng.NewGenericMessage(123)
+	el := msng.NewElement("Title", "", "", "", []messenger.Button{{
+		Type:                messenger.ButtonTypeWebURL,
+		Title:               "Open",
+		URL:                 "https://evil.example.org/cart",
+		MessengerExtensions: true,
+	}})
+	gm.AddElement(el)
+
+	var domainErr messenger.ErrDomainNotWhitelisted
+	if _, err := msng.SendMessage(gm); !errors.As(err, &domainErr) || domainErr.Domain != "evil.example.org" {
+		t.Error("Expected ErrDomainNotWhitelisted, returned", err)
+	}
+
+	el.Buttons[0].URL = "https://shop.example.com/cart"
+	gm = msng.NewGenericMessage(123)
+	gm.AddElement(el)
+	if _, err := msng.SendMessage(gm); err != nil {
+		t.Error(err)
+	}
+
+	// links opened without messenger extensions are not checked
+	gm = msng.NewGenericMessage(123)
+	gm.AddNewElement("Title", "", "https://other.example.net", "", nil)
+	if _, err := msng.SendMessage(gm); err != nil {
+		t.Error(err)
+	}
+}
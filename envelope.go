@@ -0,0 +1,60 @@
+package messenger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// MessageEnvelope holds Message with its type name so it can be stored (e.g. in database) and restored later
+type MessageEnvelope struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// messageTypes creates empty messages by type name, used to restore messages from envelope
+var messageTypes = map[string]func() Message{
+	"TextMessage":    func() Message { return &TextMessage{} },
+	"GenericMessage": func() Message { return &GenericMessage{} },
+	"ButtonMessage":  func() Message { return &ButtonMessage{} },
+
+	"OneTimeNotifRequestMessage": func() Message { return &OneTimeNotifRequestMessage{} },
+
+	"TemplateMessage[Element]":              func() Message { return &TemplateMessage[Element]{} },
+	"TemplateMessage[ListElement]":          func() Message { return &TemplateMessage[ListElement]{} },
+	"TemplateMessage[OpenGraphElement]":     func() Message { return &TemplateMessage[OpenGraphElement]{} },
+	"TemplateMessage[AirlineFlightSegment]": func() Message { return &TemplateMessage[AirlineFlightSegment]{} },
+}
+
+// WrapMessage wraps m into MessageEnvelope
+func WrapMessage(m Message) (MessageEnvelope, error) {
+	if m == nil {
+		return MessageEnvelope{}, fmt.Errorf("%w: nil message", ErrUnknownMessageType)
+	}
+	name := reflect.Indirect(reflect.ValueOf(m)).Type().Name()
+	if t, ok := m.(interface{ envelopeType() string }); ok { // generic TemplateMessage
+		name = t.envelopeType()
+	}
+	if _, ok := messageTypes[name]; !ok {
+		return MessageEnvelope{}, fmt.Errorf("%w: %s", ErrUnknownMessageType, name)
+	}
+
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return MessageEnvelope{}, err
+	}
+	return MessageEnvelope{Type: name, Payload: payload}, nil
+}
+
+// Unwrap restores message from envelope, returned message is pointer to concrete type, e.g. *TextMessage
+func (e MessageEnvelope) Unwrap() (Message, error) {
+	newMessage, ok := messageTypes[e.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMessageType, e.Type)
+	}
+	m := newMessage()
+	if err := json.Unmarshal(e.Payload, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
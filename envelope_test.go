@@ -0,0 +1,56 @@
+package messenger_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestMessageEnvelope(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+
+	text := msng.NewTextMessage(123, "hello")
+	text.MessagingType = messenger.MessagingTypeUpdate
+	text.SetReplyToMessageID("m_1")
+
+	generic := msng.NewGenericMessage(123)
+	el := msng.NewElement("Title", "Subtitle", "https://example.com", "", nil)
+	el.AddPostbackButton("Buy", "BUY")
+	generic.AddElement(el)
+	generic.NotificationType = messenger.NotificationTypeNoPush
+
+	button := msng.NewButtonMessage(123, "Choose", []messenger.Button{msng.NewPostbackButton("Yes", "YES")}, messenger.WithSharable(true))
+
+	for _, m := range []messenger.Message{&text, generic, &button} {
+		env, err := messenger.WrapMessage(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// store and restore envelope as database would
+		b, err := json.Marshal(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var stored messenger.MessageEnvelope
+		if err := json.Unmarshal(b, &stored); err != nil {
+			t.Fatal(err)
+		}
+
+		restored, err := stored.Unwrap()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := reflect.Indirect(reflect.ValueOf(m)).Interface()
+		if got := reflect.Indirect(reflect.ValueOf(restored)).Interface(); !reflect.DeepEqual(got, expected) {
+			t.Errorf("%s: expected %+v, restored %+v", env.Type, expected, got)
+		}
+	}
+
+	if _, err := (messenger.MessageEnvelope{Type: "Unknown"}).Unwrap(); !errors.Is(err, messenger.ErrUnknownMessageType) {
+		t.Error("Expected ErrUnknownMessageType, returned", err)
+	}
+}
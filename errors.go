@@ -0,0 +1,257 @@
+package messenger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Errors returned by the package, check them with errors.Is
+var (
+	ErrRateLimited     = errors.New("messenger: rate limited")
+	ErrInvalidToken    = errors.New("messenger: invalid access token")
+	ErrUserDeactivated = errors.New("messenger: user deactivated")
+	ErrUserBlocked     = errors.New("messenger: user blocked")
+	ErrPayloadTooLarge = errors.New("messenger: payload too large")
+	ErrMaxElements     = errors.New("messenger: too many elements")
+	ErrMaxButtons      = errors.New("messenger: too many buttons")
+	ErrTitleTooLong    = errors.New("messenger: title too long")
+	ErrSubtitleTooLong = errors.New("messenger: subtitle too long")
+	ErrMetadataTooLong = errors.New("messenger: metadata too long")
+	ErrNotFound        = errors.New("messenger: not found")
+
+	// ErrUserOptedOut is returned when message is not sent because user is in BlockList
+	ErrUserOptedOut = errors.New("messenger: user opted out")
+
+	// ErrElementFieldMissing is returned when required field of template element is empty
+	ErrElementFieldMissing = errors.New("messenger: template element field missing")
+
+	// ErrInvalidAspectRatio is returned for image aspect ratio other than AspectRatioHorizontal or AspectRatioSquare
+	ErrInvalidAspectRatio = errors.New("messenger: invalid image aspect ratio")
+
+	// ErrFallbackURLRequired is returned for default action with messenger extensions but without fallback URL
+	ErrFallbackURLRequired = errors.New("messenger: fallback URL required with messenger extensions")
+
+	// ErrOutsideMessagingWindow is returned when message is sent outside of allowed messaging window
+	ErrOutsideMessagingWindow = errors.New("messenger: outside of allowed messaging window")
+
+	ErrInvalidLocale = errors.New("messenger: invalid locale")
+	ErrInvalidMenu   = errors.New("messenger: invalid persistent menu")
+
+	// ErrInvalidGreeting is returned for greeting text with unknown placeholder
+	ErrInvalidGreeting = errors.New("messenger: invalid greeting")
+
+	// ErrNoMorePages is returned by PageIterator.Next when all pages are already fetched
+	ErrNoMorePages = errors.New("messenger: no more pages")
+
+	// ErrTooManyPages is returned by CollectAll when page limit is reached
+	ErrTooManyPages = errors.New("messenger: too many pages")
+
+	// ErrTokenRefresh wraps errors returned by AccessTokenProvider
+	ErrTokenRefresh = errors.New("messenger: access token refresh failed")
+
+	// ErrInvalidSignedRequest is returned when signed request is malformed or signature doesn't match
+	ErrInvalidSignedRequest = errors.New("messenger: invalid signed request")
+
+	// ErrInvalidRequest is returned when request received on webhook is not valid page event
+	ErrInvalidRequest = errors.New("messenger: invalid webhook request")
+
+	// ErrNoRetryQueue is returned by StartRetryWorker when messenger has no RetryQueue
+	ErrNoRetryQueue = errors.New("messenger: retry queue not set")
+
+	// ErrMissingTranslation is returned by SendLocalizedText when message is not translated
+	ErrMissingTranslation = errors.New("messenger: missing translation")
+
+	// ErrNoOTNStore is returned by SendStoredOTNMessage when messenger has no OTNStore
+	ErrNoOTNStore = errors.New("messenger: one time notification store not set")
+
+	// ErrNoLocalizer is returned by SendLocalizedText when messenger has no Localizer
+	ErrNoLocalizer = errors.New("messenger: localizer not set")
+
+	// ErrNoAppCredentials is returned by app level API calls when AppID or AppSecret is not set
+	ErrNoAppCredentials = errors.New("messenger: app ID and app secret required")
+
+	// ErrMissingAppSecret is returned when signed request is verified without AppSecret
+	ErrMissingAppSecret = errors.New("messenger: app secret required to verify signed request")
+
+	// ErrEmptyCarousel is returned by SendCarousel when there are no cards to send
+	ErrEmptyCarousel = errors.New("messenger: carousel has no cards")
+
+	// ErrUnsupportedAPIVersion is returned when message is not supported by Graph API version messenger uses
+	ErrUnsupportedAPIVersion = errors.New("messenger: message not supported by API version")
+
+	// ErrInvalidAttachmentURL is returned by ValidateAttachmentURL for URL that isn't accessible media
+	ErrInvalidAttachmentURL = errors.New("messenger: invalid attachment URL")
+
+	// ErrUnknownMessageType is returned when message type can't be stored in MessageEnvelope
+	ErrUnknownMessageType = errors.New("messenger: unknown message type")
+)
+
+// Errors returned when message violates Messenger Platform policy, see ValidateMessagePolicy
+var (
+	ErrInvalidMessagingType        = errors.New("messenger: invalid messaging type")
+	ErrInvalidNotificationType     = errors.New("messenger: invalid notification type")
+	ErrInvalidSubscriptionCategory = errors.New("messenger: invalid subscription category")
+	ErrMessageTagMissing           = errors.New("messenger: messaging type MESSAGE_TAG requires tag")
+	ErrTagRequiresMessageTag       = errors.New("messenger: tag requires messaging type MESSAGE_TAG")
+)
+
+// ErrDomainNotWhitelisted is returned when URL opened with messenger extensions is not on whitelisted domain
+type ErrDomainNotWhitelisted struct {
+	Domain string
+}
+
+// Error implements error interface
+func (err ErrDomainNotWhitelisted) Error() string {
+	return fmt.Sprintf("messenger: domain %s is not whitelisted", err.Domain)
+}
+
+// ErrFeatureNotSupported is returned when feature is not supported by Graph API version messenger uses,
+// it matches ErrUnsupportedAPIVersion
+type ErrFeatureNotSupported struct {
+	Feature         Feature
+	RequiredVersion string
+	CurrentVersion  string
+}
+
+// Error implements error interface
+func (err ErrFeatureNotSupported) Error() string {
+	return fmt.Sprintf("messenger: %s requires API version %s, using %s", err.Feature, err.RequiredVersion, err.CurrentVersion)
+}
+
+// Unwrap returns ErrUnsupportedAPIVersion
+func (err ErrFeatureNotSupported) Unwrap() error {
+	return ErrUnsupportedAPIVersion
+}
+
+// FacebookAPIError is error returned by Facebook Graph API, use errors.As to get it from returned error
+type FacebookAPIError FacebookError
+
+// Error implements error interface
+func (err FacebookAPIError) Error() string {
+	return fmt.Sprintf("FB Error: Type %s: %s; FB trace ID: %s", err.Type, err.Message, err.FbtraceID)
+}
+
+// HasCode reports whether error has Graph API error code
+func (err FacebookAPIError) HasCode(code ErrorCode) bool {
+	return ErrorCode(err.Code) == code
+}
+
+// ErrorCode is Graph API error code, see FacebookAPIError.HasCode
+type ErrorCode int
+
+// Graph API error codes
+const (
+	ErrCodeAPIUnknown            = ErrorCode(1)
+	ErrCodeAPIService            = ErrorCode(2)
+	ErrCodeAPITooManyCalls       = ErrorCode(4)
+	ErrCodePermissionsError      = ErrorCode(10)
+	ErrCodeAPIUserTooManyCalls   = ErrorCode(17)
+	ErrCodePageTooManyCalls      = ErrorCode(32)
+	ErrCodeMessageNotDeliver     = ErrorCode(100) // invalid parameter, e.g. no matching user found
+	ErrCodeAPISession            = ErrorCode(102)
+	ErrCodeOAuthException        = ErrorCode(190)
+	ErrCodePermissionDenied      = ErrorCode(200)
+	ErrCodeApplicationLimit      = ErrorCode(341)
+	ErrCodePolicyViolation       = ErrorCode(368) // page temporarily blocked for policy violations
+	ErrCodeUserBlocked           = ErrorCode(551) // this person isn't available right now
+	ErrCodeRateLimit             = ErrorCode(613)
+	ErrCodeTemporarySendFailure  = ErrorCode(1200)
+	ErrCodeAccountLinkingExpired = ErrorCode(10303)
+)
+
+var errorCodeDescriptions = map[ErrorCode]string{
+	ErrCodeAPIUnknown:            "unknown error, retry later",
+	ErrCodeAPIService:            "service temporarily unavailable",
+	ErrCodeAPITooManyCalls:       "application request limit reached",
+	ErrCodePermissionsError:      "permission not granted or removed",
+	ErrCodeAPIUserTooManyCalls:   "user request limit reached",
+	ErrCodePageTooManyCalls:      "page request limit reached",
+	ErrCodeMessageNotDeliver:     "invalid parameter",
+	ErrCodeAPISession:            "session key invalid or no longer valid",
+	ErrCodeOAuthException:        "access token expired or invalid",
+	ErrCodePermissionDenied:      "permission denied",
+	ErrCodeApplicationLimit:      "application limit reached",
+	ErrCodePolicyViolation:       "action deemed abusive or disallowed",
+	ErrCodeUserBlocked:           "person isn't available right now",
+	ErrCodeRateLimit:             "calls exceeded rate limit",
+	ErrCodeTemporarySendFailure:  "temporary send message failure, retry later",
+	ErrCodeAccountLinkingExpired: "account linking token expired",
+}
+
+// Description returns human readable description of error code
+func (code ErrorCode) Description() string {
+	if d, ok := errorCodeDescriptions[code]; ok {
+		return d
+	}
+	return fmt.Sprintf("unknown error code %d", int(code))
+}
+
+// codeErrors maps known Graph API error codes to package errors
+var codeErrors = map[ErrorCode]error{
+	ErrCodeAPITooManyCalls:     ErrRateLimited,
+	ErrCodeAPIUserTooManyCalls: ErrRateLimited,
+	ErrCodePageTooManyCalls:    ErrRateLimited,
+	ErrCodeRateLimit:           ErrRateLimited,
+	ErrCodeOAuthException:      ErrInvalidToken,
+	ErrCodeUserBlocked:         ErrUserBlocked,
+}
+
+// wrapFacebookError converts error received from Facebook into Go error,
+// known error codes are also wrapped with matching package error
+func wrapFacebookError(fbErr *FacebookError) error {
+	apiErr := FacebookAPIError(*fbErr)
+	if apiErr.HasCode(ErrCodeUserBlocked) && fbErr.ErrorSubcode == 1545041 { // person isn't available, account deactivated
+		return fmt.Errorf("%w: %w", ErrUserDeactivated, apiErr)
+	}
+	if err, ok := codeErrors[ErrorCode(fbErr.Code)]; ok {
+		return fmt.Errorf("%w: %w", err, apiErr)
+	}
+	if apiErr.HasCode(ErrCodeMessageNotDeliver) && fbErr.ErrorSubcode == 2018001 { // no matching user found
+		return fmt.Errorf("%w: %w", ErrNotFound, apiErr)
+	}
+	if apiErr.HasCode(ErrCodePermissionsError) && fbErr.ErrorSubcode == 2018278 { // message sent outside of allowed window
+		return fmt.Errorf("%w: %w", ErrOutsideMessagingWindow, apiErr)
+	}
+	return fmt.Errorf("messenger: %w", apiErr)
+}
+
+// responseError returns error received from Graph API in response with statusCode and body b, nil if there is no error.
+// Error responses that are not JSON (e.g. HTML error pages from proxies) are returned as FacebookAPIError too,
+// server errors get code 2 (service unavailable) so they are retried
+func responseError(statusCode int, b []byte) error {
+	var reply struct {
+		Error *FacebookError `json:"error"`
+	}
+	if err := json.Unmarshal(b, &reply); err == nil && reply.Error != nil {
+		return reply.Error.Error()
+	}
+	if statusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	fbErr := FacebookError{
+		Type:    "HTTPError",
+		Message: fmt.Sprintf("HTTP %d %s", statusCode, http.StatusText(statusCode)),
+	}
+	if statusCode >= http.StatusInternalServerError {
+		fbErr.Code = int(ErrCodeAPIService)
+	}
+	if body := strings.TrimSpace(string(b)); body != "" {
+		if len(body) > 200 {
+			n := 200
+			for n > 0 && !utf8.RuneStart(body[n]) { // don't cut multi-byte character
+				n--
+			}
+			body = body[:n] + "..."
+		}
+		fbErr.Message += ": " + body
+	}
+	if statusCode == http.StatusRequestEntityTooLarge {
+		return fmt.Errorf("%w: %w", ErrPayloadTooLarge, FacebookAPIError(fbErr))
+	}
+	return wrapFacebookError(&fbErr)
+}
@@ -0,0 +1,152 @@
+package messenger_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+// sendWithFacebookError sends text message to mock FB server that replies with error code and subcode
+func sendWithFacebookError(t *testing.T, code, subcode int) error {
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := json.Marshal(map[string]messenger.FacebookError{
+			"error": {Code: code, ErrorSubcode: subcode, Type: "OAuthException", Message: "test", FbtraceID: "trace"},
+		})
+		w.Write(b)
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	_, err := msng.SendTextMessage(12123213123, "hello")
+	return err
+}
+
+func TestErrorCodes(t *testing.T) {
+	tests := []struct {
+		code    int
+		subcode int
+		want    error
+	}{
+		{190, 0, messenger.ErrInvalidToken},
+		{4, 0, messenger.ErrRateLimited},
+		{613, 0, messenger.ErrRateLimited},
+		{551, 0, messenger.ErrUserBlocked},
+		{551, 1545041, messenger.ErrUserDeactivated},
+		{100, 2018001, messenger.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		err := sendWithFacebookError(t, tt.code, tt.subcode)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("code %d: expected %v, returned %v", tt.code, tt.want, err)
+		}
+
+		var apiErr messenger.FacebookAPIError
+		if !errors.As(err, &apiErr) || apiErr.Code != tt.code {
+			t.Errorf("code %d: expected FacebookAPIError, returned %v", tt.code, err)
+		}
+	}
+}
+
+func TestErrorUnknownCode(t *testing.T) {
+	err := sendWithFacebookError(t, 1, 0)
+	if err == nil || errors.Is(err, messenger.ErrRateLimited) || errors.Is(err, messenger.ErrInvalidToken) {
+		t.Error("Unexpected error for unknown code", err)
+	}
+	if !errors.As(err, &messenger.FacebookAPIError{}) {
+		t.Error("Expected FacebookAPIError, returned", err)
+	}
+}
+
+func TestHTMLErrorResponse(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html><body>502 Bad Gateway</body></html>"))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	resp, err := msng.SendTextMessage(12123213123, "hello")
+	var apiErr messenger.FacebookAPIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 2 || apiErr.Type != "HTTPError" {
+		t.Fatal("Expected FacebookAPIError, returned", err)
+	}
+	if resp.IsSuccess() {
+		t.Error("Expected unsuccessful response", resp)
+	}
+}
+
+func TestPayloadTooLarge(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	_, err := msng.SendTextMessage(12123213123, "hello")
+	if !errors.Is(err, messenger.ErrPayloadTooLarge) || !errors.As(err, &messenger.FacebookAPIError{}) {
+		t.Error("Expected ErrPayloadTooLarge, returned", err)
+	}
+}
+
+func TestLongErrorResponse(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(strings.Repeat("a", 199) + strings.Repeat("é", 100)))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	_, err := msng.SendTextMessage(12123213123, "hello")
+	var apiErr messenger.FacebookAPIError
+	if !errors.As(err, &apiErr) {
+		t.Fatal("Expected FacebookAPIError, returned", err)
+	}
+	if !utf8.ValidString(apiErr.Message) || !strings.HasSuffix(apiErr.Message, "a...") {
+		t.Error("Expected body truncated before multi-byte character", apiErr.Message)
+	}
+}
+
+func TestFacebookResponse(t *testing.T) {
+	resp := messenger.FacebookResponse{MessageID: "mid.1", RecipientID: 42}
+	if !resp.IsSuccess() || resp.String() != "FacebookResponse{MessageID: mid.1, RecipientID: 42}" {
+		t.Error("Unexpected response", resp.IsSuccess(), resp.String())
+	}
+}
+
+func TestFacebookAPIErrorHasCode(t *testing.T) {
+	// error payloads from Messenger Platform documentation
+	tests := []struct {
+		payload string
+		code    messenger.ErrorCode
+	}{
+		{`{"error":{"message":"Invalid OAuth access token.","type":"OAuthException","code":190,"error_subcode":460,"fbtrace_id":"EJplcsCHuLu"}}`, messenger.ErrCodeOAuthException},
+		{`{"error":{"message":"(#4) Application request limit reached","type":"OAuthException","is_transient":true,"code":4,"fbtrace_id":"BLBz/WZt8dN"}}`, messenger.ErrCodeAPITooManyCalls},
+		{`{"error":{"message":"(#551) This person isn't available right now.","type":"OAuthException","code":551,"error_subcode":1545041,"fbtrace_id":"HDjKiWT4Vgx"}}`, messenger.ErrCodeUserBlocked},
+		{`{"error":{"message":"(#100) No matching user found","type":"OAuthException","code":100,"error_subcode":2018001,"fbtrace_id":"H3L9sJbKt1e"}}`, messenger.ErrCodeMessageNotDeliver},
+		{`{"error":{"message":"(#10) This message is sent outside of allowed window.","type":"OAuthException","code":10,"error_subcode":2018278,"fbtrace_id":"DTK4YqLM7sx"}}`, messenger.ErrCodePermissionsError},
+		{`{"error":{"message":"(#1200) Temporary send message failure. Please try again later","type":"OAuthException","code":1200,"fbtrace_id":"G4Da6jN5nzQ"}}`, messenger.ErrCodeTemporarySendFailure},
+	}
+
+	for _, tt := range tests {
+		mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(tt.payload))
+		})
+		msng := messenger.New("XXXXXXX", "", mock)
+		_, err := msng.SendTextMessage(12123213123, "hello")
+
+		var apiErr messenger.FacebookAPIError
+		if !errors.As(err, &apiErr) || !apiErr.HasCode(tt.code) || apiErr.HasCode(messenger.ErrCodeAPIUnknown) {
+			t.Errorf("Expected code %d (%s), returned %v", tt.code, tt.code.Description(), err)
+		}
+	}
+
+	if d := messenger.ErrorCode(12345).Description(); d != "unknown error code 12345" {
+		t.Error("Unexpected description", d)
+	}
+}
@@ -0,0 +1,123 @@
+package messenger
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// WebhookEvent is single messaging event received on webhook, recorded to EventLog
+type WebhookEvent struct {
+	Timestamp   time.Time       `json:"timestamp"`
+	SenderID    int64           `json:"sender_id"`
+	RecipientID int64           `json:"recipient_id"`
+	Type        string          `json:"type"`
+	RawJSON     json.RawMessage `json:"raw_json"`
+}
+
+// EventLog records all events received on webhook, use it for debugging or audit trail
+type EventLog interface {
+	Record(ctx context.Context, e WebhookEvent) error
+}
+
+// WithEventLog sets event log that records all received webhook events
+func WithEventLog(l EventLog) Option {
+	return func(msng *Messenger) {
+		msng.EventLog = l
+	}
+}
+
+// recordEvent records event to EventLog if set, errors are only logged since event is already dispatched
+func (msng *Messenger) recordEvent(ctx context.Context, e WebhookEvent) {
+	if msng.EventLog == nil {
+		return
+	}
+	if err := msng.EventLog.Record(ctx, e); err != nil {
+		msng.logger().Error("event log record failed", "event_type", e.Type, "sender_id", e.SenderID, "error", err)
+	}
+}
+
+// rawMessagingEvents returns raw JSON of each messaging event in webhook request body, indexed by entry
+func rawMessagingEvents(body []byte) [][]json.RawMessage {
+	var raw struct {
+		Entry []struct {
+			Messaging []json.RawMessage `json:"messaging"`
+		} `json:"entry"`
+	}
+	json.Unmarshal(body, &raw)
+
+	events := make([][]json.RawMessage, len(raw.Entry))
+	for i, entry := range raw.Entry {
+		events[i] = entry.Messaging
+	}
+	return events
+}
+
+// MemoryEventLog keeps last received events in memory, useful for tests
+type MemoryEventLog struct {
+	mu         sync.Mutex
+	maxEntries int
+	events     []WebhookEvent
+}
+
+// NewMemoryEventLog creates event log that keeps up to maxEntries last events, 0 for unlimited
+func NewMemoryEventLog(maxEntries int) *MemoryEventLog {
+	return &MemoryEventLog{maxEntries: maxEntries}
+}
+
+// Record implements EventLog
+func (l *MemoryEventLog) Record(ctx context.Context, e WebhookEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, e)
+	if l.maxEntries > 0 && len(l.events) > l.maxEntries {
+		l.events = l.events[len(l.events)-l.maxEntries:]
+	}
+	return nil
+}
+
+// Events returns copy of recorded events, oldest first
+func (l *MemoryEventLog) Events() []WebhookEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]WebhookEvent(nil), l.events...)
+}
+
+// Clear removes all recorded events
+func (l *MemoryEventLog) Clear() {
+	l.mu.Lock()
+	l.events = nil
+	l.mu.Unlock()
+}
+
+// FileEventLog appends received events to file, one JSON object per line
+type FileEventLog struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+// NewFileEventLog opens or creates file at path for appending events
+func NewFileEventLog(path string) (*FileEventLog, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileEventLog{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Record implements EventLog
+func (l *FileEventLog) Record(ctx context.Context, e WebhookEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.enc.Encode(e)
+}
+
+// Close closes underlying file
+func (l *FileEventLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Close()
+}
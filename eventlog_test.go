@@ -0,0 +1,59 @@
+package messenger_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+const webhookMessage = `{"object":"page","entry":[{"id":"1","time":1458692752478,"messaging":[` +
+	`{"sender":{"id":"12123213123"},"recipient":{"id":"1"},"timestamp":1458692752478,"message":{"mid":"mid.1","seq":1,"text":"hello"}},` +
+	`{"sender":{"id":"12123213123"},"recipient":{"id":"1"},"timestamp":1458692752479,"read":{"watermark":1458668856253,"seq":2}}]}]}`
+
+func TestEventLog(t *testing.T) {
+	log := messenger.NewMemoryEventLog(1)
+	msng := messenger.New("XXXXXXX", "1", messenger.WithEventLog(log))
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage))
+	msng.ServeHTTP(httptest.NewRecorder(), r)
+
+	events := log.Events()
+	if len(events) != 1 {
+		t.Fatal("Expected 1 event, recorded", len(events))
+	}
+
+	e := events[0]
+	if e.Type != "read" || e.SenderID != 12123213123 || e.RecipientID != 1 || e.Timestamp.UnixMilli() != 1458692752479 {
+		t.Error("Unexpected event", e)
+	}
+	if !strings.Contains(string(e.RawJSON), `"watermark":1458668856253`) {
+		t.Error("Unexpected raw JSON", string(e.RawJSON))
+	}
+
+	log.Clear()
+	if len(log.Events()) != 0 {
+		t.Error("Events not cleared")
+	}
+}
+
+func TestCanSendMessage(t *testing.T) {
+	log := messenger.NewMemoryEventLog(0)
+	msng := messenger.New("XXXXXXX", "1", messenger.WithEventLog(log))
+
+	// message in webhookMessage is from 2016
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage)))
+	ok, reason, err := msng.CanSendMessage(context.Background(), "12123213123")
+	if ok || reason != messenger.ReasonWindowClosed || err != nil {
+		t.Error("Expected closed window, returned", ok, reason, err)
+	}
+
+	log.Record(context.Background(), messenger.WebhookEvent{Timestamp: time.Now(), SenderID: 12123213123, Type: "postback"})
+	ok, reason, err = msng.CanSendMessage(context.Background(), "12123213123")
+	if !ok || reason != messenger.ReasonWindowOpen || err != nil {
+		t.Error("Expected open window, returned", ok, reason, err)
+	}
+}
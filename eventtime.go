@@ -0,0 +1,44 @@
+package messenger
+
+import "time"
+
+// EventTime returns time when message was sent
+func (m FacebookMessage) EventTime() time.Time { return time.UnixMilli(m.Timestamp) }
+
+// Age returns time passed since message was sent, use it to skip stale events
+func (m FacebookMessage) Age() time.Duration { return time.Since(m.EventTime()) }
+
+// EventTime returns time of delivery event
+func (d FacebookDelivery) EventTime() time.Time { return time.UnixMilli(d.Timestamp) }
+
+// Age returns time passed since delivery event
+func (d FacebookDelivery) Age() time.Duration { return time.Since(d.EventTime()) }
+
+// EventTime returns time of read event
+func (r FacebookRead) EventTime() time.Time { return time.UnixMilli(r.Timestamp) }
+
+// Age returns time passed since read event
+func (r FacebookRead) Age() time.Duration { return time.Since(r.EventTime()) }
+
+// EventTime returns time when postback button was pressed
+func (p FacebookPostback) EventTime() time.Time { return time.UnixMilli(p.Timestamp) }
+
+// Age returns time passed since postback button was pressed
+func (p FacebookPostback) Age() time.Duration { return time.Since(p.EventTime()) }
+
+// setEventTimes copies event timestamp to message, delivery, postback and read, so EventTime works
+func (e MessagingEntry) setEventTimes() {
+	ts := int64(e.Timestamp)
+	if e.Message != nil {
+		e.Message.Timestamp = ts
+	}
+	if e.Delivery != nil {
+		e.Delivery.Timestamp = ts
+	}
+	if e.Postback != nil {
+		e.Postback.Timestamp = ts
+	}
+	if e.Read != nil {
+		e.Read.Timestamp = ts
+	}
+}
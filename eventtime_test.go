@@ -0,0 +1,62 @@
+package messenger_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestEventTime(t *testing.T) {
+	const ms = 1458692752478
+	expected := time.Date(2016, 3, 23, 0, 25, 52, 478000000, time.UTC)
+
+	if got := (messenger.FacebookMessage{Timestamp: ms}).EventTime(); !got.Equal(expected) || got.UnixMilli() != ms {
+		t.Error("Expected", expected, "returned", got)
+	}
+	if got := (messenger.FacebookDelivery{Timestamp: ms}).EventTime(); !got.Equal(expected) {
+		t.Error("Expected", expected, "returned", got)
+	}
+	if got := (messenger.FacebookPostback{Timestamp: ms}).EventTime(); !got.Equal(expected) {
+		t.Error("Expected", expected, "returned", got)
+	}
+	if age := (messenger.FacebookRead{Timestamp: ms}).Age(); age < 24*time.Hour {
+		t.Error("Expected stale event, age", age)
+	}
+
+	received := make(chan messenger.FacebookRead, 1)
+	msng := messenger.New("XXXXXXX", "1")
+	msng.ReadReceived = func(msng *messenger.Messenger, userID int64, r messenger.FacebookRead) {
+		received <- r
+	}
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage)))
+	if r := <-received; r.EventTime().UnixMilli() != 1458692752479 {
+		t.Error("Expected event timestamp from webhook, returned", r.Timestamp)
+	}
+
+	rq, err := messenger.DecodeRequest(httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range rq.Entry[0].Messaging {
+		if e.Message != nil && e.Message.EventTime().UnixMilli() != int64(e.Timestamp) {
+			t.Error("Expected decoded message timestamp, returned", e.Message.Timestamp)
+		}
+		if e.Read != nil && e.Read.EventTime().UnixMilli() != 1458692752479 {
+			t.Error("Expected decoded read timestamp, returned", e.Read.Timestamp)
+		}
+	}
+
+	var own messenger.FacebookRequest
+	if err := json.Unmarshal([]byte(webhookMessage), &own); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range own.Entry[0].Messaging {
+		if e.Read != nil && e.Read.EventTime().UnixMilli() != 1458692752479 {
+			t.Error("Expected read timestamp when request is unmarshaled directly, returned", e.Read.Timestamp)
+		}
+	}
+}
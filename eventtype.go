@@ -0,0 +1,77 @@
+package messenger
+
+// EventType is type of messaging event received on webhook, see MessagingEntry.EventType
+type EventType string
+
+// Event types of messaging events
+const (
+	// EventTypeMessage is message sent by user, "messages" webhook event
+	EventTypeMessage = EventType("message")
+
+	// EventTypeDelivery is delivery report of sent messages, "message_deliveries" webhook event
+	EventTypeDelivery = EventType("delivery")
+
+	// EventTypeRead is read report of sent messages, "message_reads" webhook event
+	EventTypeRead = EventType("read")
+
+	// EventTypePostback is postback button or get started tap, "messaging_postbacks" webhook event
+	EventTypePostback = EventType("postback")
+
+	// EventTypeOptin is plugin or one time notification opt in, "messaging_optins" webhook event
+	EventTypeOptin = EventType("optin")
+
+	// EventTypeReferral is conversation opened with ref parameter, "messaging_referrals" webhook event
+	EventTypeReferral = EventType("referral")
+
+	// EventTypeEcho is echo of message sent by page, "message_echoes" webhook event
+	EventTypeEcho = EventType("message_echo")
+
+	// EventTypeUnsend is message removed by user, "messages" webhook event with is_deleted set
+	EventTypeUnsend = EventType("message_unsend")
+
+	// EventTypeReaction is reaction to message, "message_reactions" webhook event
+	EventTypeReaction = EventType("reaction")
+
+	// EventTypePassThreadControl is thread control passed to your app, "messaging_handovers" webhook event
+	EventTypePassThreadControl = EventType("pass_thread_control")
+
+	// EventTypeRequestThreadControl is request of secondary receiver for thread control, "messaging_handovers" webhook event
+	EventTypeRequestThreadControl = EventType("request_thread_control")
+
+	// EventTypeStandby is event received while other app controls the thread, "standby" webhook event
+	EventTypeStandby = EventType("standby")
+
+	// EventTypeUnknown is event of type not supported by the package
+	EventTypeUnknown = EventType("unknown")
+)
+
+// EventType returns type of messaging event
+func (e MessagingEntry) EventType() EventType {
+	switch {
+	case e.standby:
+		return EventTypeStandby
+	case e.Message != nil && e.Message.IsEcho:
+		return EventTypeEcho
+	case e.Message != nil && e.Message.IsDeleted:
+		return EventTypeUnsend
+	case e.Message != nil:
+		return EventTypeMessage
+	case e.Delivery != nil:
+		return EventTypeDelivery
+	case e.Postback != nil:
+		return EventTypePostback
+	case e.Optin != nil:
+		return EventTypeOptin
+	case e.Read != nil:
+		return EventTypeRead
+	case e.Referral != nil:
+		return EventTypeReferral
+	case e.Reaction != nil:
+		return EventTypeReaction
+	case e.PassThreadControl != nil:
+		return EventTypePassThreadControl
+	case e.RequestThreadControl != nil:
+		return EventTypeRequestThreadControl
+	}
+	return EventTypeUnknown
+}
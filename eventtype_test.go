@@ -0,0 +1,90 @@
+package messenger_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestEventType(t *testing.T) {
+	body := `{"object":"page","entry":[{"id":1,"time":1,
+		"messaging":[
+			{"sender":{"id":"7"},"message":{"mid":"m1","text":"hi"}},
+			{"sender":{"id":"7"},"message":{"mid":"m2","text":"hi","is_echo":true}},
+			{"sender":{"id":"7"},"message":{"mid":"m3","is_deleted":true}},
+			{"sender":{"id":"7"},"delivery":{"mids":["m1"]}},
+			{"sender":{"id":"7"},"read":{"watermark":1}},
+			{"sender":{"id":"7"},"postback":{"payload":"P"}},
+			{"sender":{"id":"7"},"optin":{"ref":"R"}},
+			{"sender":{"id":"7"},"referral":{"ref":"R","source":"SHORTLINK"}},
+			{"sender":{"id":"7"},"reaction":{"mid":"m1","action":"react","emoji":"❤"}},
+			{"sender":{"id":"7"},"pass_thread_control":{"new_owner_app_id":"1"}},
+			{"sender":{"id":"7"}}
+		],
+		"standby":[{"sender":{"id":"7"},"message":{"mid":"m4","text":"hi"}}]}]}`
+	fbRq, err := messenger.DecodeRequest(httptest.NewRequest("POST", "/", bytes.NewBufferString(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []messenger.EventType{
+		messenger.EventTypeMessage,
+		messenger.EventTypeEcho,
+		messenger.EventTypeUnsend,
+		messenger.EventTypeDelivery,
+		messenger.EventTypeRead,
+		messenger.EventTypePostback,
+		messenger.EventTypeOptin,
+		messenger.EventTypeReferral,
+		messenger.EventTypeReaction,
+		messenger.EventTypePassThreadControl,
+		messenger.EventTypeUnknown,
+	}
+	entry := fbRq.Entry[0]
+	for i, e := range entry.Messaging {
+		if got := e.EventType(); got != expected[i] {
+			t.Errorf("Event %d: expected %s, got %s", i, expected[i], got)
+		}
+	}
+	if got := entry.Standby[0].EventType(); got != messenger.EventTypeStandby {
+		t.Error("Expected standby event, got", got)
+	}
+}
+
+func TestEntryPageIDAndTime(t *testing.T) {
+	body := `{"object":"page","entry":[` +
+		`{"id":"111","time":1458692752478,"messaging":[{"sender":{"id":"7"},"recipient":{"id":"111"},"message":{"mid":"m1","text":"hi"}}]},` +
+		`{"id":222,"time":1458692753000,"messaging":[{"sender":{"id":"8"},"recipient":{"id":"222"},"message":{"mid":"m2","text":"hi"}}]}]}`
+	fbRq, err := messenger.DecodeRequest(httptest.NewRequest("POST", "/", bytes.NewBufferString(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fbRq.Entry) != 2 {
+		t.Fatal("Expected 2 entries, decoded", len(fbRq.Entry))
+	}
+	if e := fbRq.Entry[0]; e.PageID() != "111" || e.EntryTime().UnixMilli() != 1458692752478 || len(e.Messaging) != 1 {
+		t.Error("Unexpected first entry", e)
+	}
+	if e := fbRq.Entry[1]; e.PageID() != "222" || e.Time != 1458692753000 || e.Messaging[0].Sender.ID != 8 {
+		t.Error("Unexpected second entry", e)
+	}
+}
+
+func TestEntryKind(t *testing.T) {
+	tests := []struct {
+		entry                           messenger.Entry
+		messaging, changes, isMessaging bool
+	}{
+		{messenger.Entry{Messaging: []messenger.MessagingEntry{{}}}, true, false, true},
+		{messenger.Entry{Standby: []messenger.MessagingEntry{{}}}, false, false, true},
+		{messenger.Entry{Messaging: []messenger.MessagingEntry{}, Changes: []messenger.FeedChange{{}}}, false, true, false},
+		{messenger.Entry{}, false, false, false},
+	}
+	for i, tt := range tests {
+		if tt.entry.HasMessaging() != tt.messaging || tt.entry.HasChanges() != tt.changes || tt.entry.IsMessagingEntry() != tt.isMessaging {
+			t.Error("Unexpected entry kind", i)
+		}
+	}
+}
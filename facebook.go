@@ -1,46 +1,182 @@
 package messenger
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
 
 // FacebookRequest received from Facebook server on webhook, contains messages, delivery reports and/or postbacks
 type FacebookRequest struct {
-	Entry []struct {
-		ID        int64 `json:"id"`
-		Messaging []struct {
-			Recipient struct {
-				ID int64 `json:"id,string"`
-			} `json:"recipient"`
-			Sender struct {
-				ID int64 `json:"id,string"`
-			} `json:"sender"`
-			Timestamp int               `json:"timestamp"`
-			Message   *FacebookMessage  `json:"message,omitempty"`
-			Delivery  *FacebookDelivery `json:"delivery"`
-			Postback  *FacebookPostback `json:"postback"`
-			Optin  	  *FacebookOptin    `json:"optin"`
-			Read	  *FacebookRead     `json:"read"`
-		} `json:"messaging"`
-		Time int `json:"time"`
-	} `json:"entry"`
-	Object string `json:"object"`
-}
-
-//
+	Entry  []Entry `json:"entry"`
+	Object string  `json:"object"`
+}
+
+// ForEach calls fn for every messaging event of every entry in request, standby events are not included.
+// With Go 1.23 or newer, Entries and MessagingEvents iterators can be used instead
+func (r FacebookRequest) ForEach(fn func(MessagingEntry)) {
+	for _, entry := range r.Entry {
+		for _, msg := range entry.Messaging {
+			fn(msg)
+		}
+	}
+}
+
+// Entry of FacebookRequest holds events of one page
+type Entry struct {
+	ID        string           `json:"id"`   // page ID
+	Time      int64            `json:"time"` // Unix milliseconds
+	Messaging []MessagingEntry `json:"messaging"`
+	Standby   []MessagingEntry `json:"standby"` // events received while other app controls the thread
+	Changes   []FeedChange     `json:"changes"`
+}
+
+// entryFields is Entry without UnmarshalJSON method
+type entryFields Entry
+
+// rawEntry is Entry with page ID as received, JSON number or string
+type rawEntry struct {
+	entryFields
+	ID json.RawMessage `json:"id"`
+}
+
+// UnmarshalJSON decodes entry, page ID can be received as JSON number or string
+func (e *Entry) UnmarshalJSON(b []byte) error {
+	var raw rawEntry
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*e = Entry(raw.entryFields)
+	e.ID = strings.Trim(string(raw.ID), `"`)
+	for i := range e.Standby {
+		e.Standby[i].standby = true
+	}
+	return nil
+}
+
+// PageID returns ID of page entry events are for
+func (e Entry) PageID() string {
+	return e.ID
+}
+
+// HasMessaging reports if entry has messaging events
+func (e Entry) HasMessaging() bool {
+	return len(e.Messaging) > 0
+}
+
+// HasChanges reports if entry has page feed changes, they are received by FeedReceived handler
+// when page is subscribed to feed webhook field
+func (e Entry) HasChanges() bool {
+	return len(e.Changes) > 0
+}
+
+// IsMessagingEntry reports if entry has messaging or standby events, other entries are page feed changes
+func (e Entry) IsMessagingEntry() bool {
+	return e.HasMessaging() || len(e.Standby) > 0
+}
+
+// EntryTime returns time of entry
+func (e Entry) EntryTime() time.Time {
+	return time.UnixMilli(e.Time)
+}
+
+// MessagingEntry is single messaging event received in FacebookRequest, see EventType
+type MessagingEntry struct {
+	Recipient struct {
+		ID int64 `json:"id,string"`
+	} `json:"recipient"`
+	Sender struct {
+		ID int64 `json:"id,string"`
+	} `json:"sender"`
+	Timestamp int               `json:"timestamp"`
+	Message   *FacebookMessage  `json:"message,omitempty"`
+	Delivery  *FacebookDelivery `json:"delivery"`
+	Postback  *FacebookPostback `json:"postback"`
+	Optin     *FacebookOptin    `json:"optin"`
+	Read      *FacebookRead     `json:"read"`
+	Referral  *FacebookReferral `json:"referral"`
+	Reaction  *FacebookReaction `json:"reaction"`
+
+	PassThreadControl    *HandoverEvent `json:"pass_thread_control"`
+	RequestThreadControl *HandoverEvent `json:"request_thread_control"`
+
+	standby bool        // received in standby channel
+	event   *eventScope // set while event is dispatched through middleware chain
+}
+
+// messagingFields is MessagingEntry without UnmarshalJSON method
+type messagingFields MessagingEntry
+
+// UnmarshalJSON decodes messaging event and copies its timestamp to message, delivery, postback and read
+func (e *MessagingEntry) UnmarshalJSON(b []byte) error {
+	var raw messagingFields
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*e = MessagingEntry(raw)
+	e.setEventTimes()
+	return nil
+}
+
+// FacebookReferral is received when user opens conversation through m.me link, ad or chat plugin with ref parameter
+type FacebookReferral struct {
+	Ref    string `json:"ref"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+}
+
+// FacebookReaction is received when user reacts to message or removes reaction
+type FacebookReaction struct {
+	Mid      string `json:"mid"`
+	Action   string `json:"action"` // "react" or "unreact"
+	Reaction string `json:"reaction,omitempty"`
+	Emoji    string `json:"emoji,omitempty"`
+}
+
 type FacebookRead struct {
-	Watermark int `json:"watermark"`
-	Seq int `json:"seq"`
+	Watermark int   `json:"watermark"`
+	Seq       int   `json:"seq"`
+	Timestamp int64 `json:"timestamp,omitempty"` // event time in Unix milliseconds, see EventTime
 }
 
-//
 type FacebookOptin struct {
-	Ref  string `json:"ref"`
+	Ref string `json:"ref"`
+
+	// one time notification optin only, see NewOneTimeNotifRequest
+	Type              string `json:"type"`    // one_time_notif_req
+	Payload           string `json:"payload"` // payload of the request
+	OneTimeNotifToken string `json:"one_time_notif_token"`
 }
 
 // FacebookMessage struct for text messaged received from facebook server as part of FacebookRequest struct
 type FacebookMessage struct {
-	Mid  string `json:"mid"`
-	Seq  int    `json:"seq"`
-	Text string `json:"text"`
+	Mid      string `json:"mid"`
+	Seq      int    `json:"seq"`
+	Text     string `json:"text"`
+	Metadata string `json:"metadata"` // set in message echo if sent message had metadata
+
+	IsEcho    bool `json:"is_echo"`    // message is echo of message sent by page
+	IsDeleted bool `json:"is_deleted"` // user unsent the message
+
+	// ReplyTo is set when user replied to specific message in the thread
+	ReplyTo *MessageReference `json:"reply_to"`
+
+	// Attachments are images, videos, files, location... sent by user
+	Attachments []Attachment `json:"attachments"`
+
+	// QuickReply is set when user tapped quick reply
+	QuickReply *FacebookQuickReply `json:"quick_reply"`
+
+	// NLP is set when built-in NLP is enabled for the page
+	NLP *MessageNLP `json:"nlp"`
+
+	Timestamp int64 `json:"timestamp,omitempty"` // event time in Unix milliseconds, see EventTime
+}
+
+// FacebookQuickReply is quick reply user tapped, received as part of FacebookMessage
+type FacebookQuickReply struct {
+	Payload string `json:"payload"`
 }
 
 // FacebookDelivery struct for delivery reports received from Facebook server as part of FacebookRequest struct
@@ -48,11 +184,42 @@ type FacebookDelivery struct {
 	Mids      []string `json:"mids"`
 	Seq       int      `json:"seq"`
 	Watermark int      `json:"watermark"`
+	Timestamp int64    `json:"timestamp,omitempty"` // event time in Unix milliseconds, see EventTime
+}
+
+// FacebookFeedEntry is page feed change (posts, comments, likes) received from Facebook server
+// when page is subscribed to feed webhook field
+type FacebookFeedEntry struct {
+	ID      string       `json:"id"`
+	Time    int64        `json:"time"`
+	Changes []FeedChange `json:"changes"`
+}
+
+// FeedChange is single change in FacebookFeedEntry, Field is subscribed webhook field, usually "feed"
+type FeedChange struct {
+	Field string          `json:"field"`
+	Value FeedChangeValue `json:"value"`
+}
+
+// FeedChangeValue describes what has changed on the page feed
+type FeedChangeValue struct {
+	Item        string `json:"item"` // post, comment, reaction, like...
+	Verb        string `json:"verb"` // add, edited, remove...
+	PostID      string `json:"post_id"`
+	CommentID   string `json:"comment_id"`
+	ParentID    string `json:"parent_id"`
+	Message     string `json:"message"`
+	CreatedTime int64  `json:"created_time"`
+	From        struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"from"`
 }
 
 // FacebookPostback struct for postbacks received from Facebook server  as part of FacebookRequest struct
 type FacebookPostback struct {
-	Payload string `json:"payload"`
+	Payload   string `json:"payload"`
+	Timestamp int64  `json:"timestamp,omitempty"` // event time in Unix milliseconds, see EventTime
 }
 
 // rawFBResponse received from Facebook server after sending the message
@@ -69,15 +236,27 @@ type FacebookResponse struct {
 	RecipientID int64  `json:"recipient_id,string"`
 }
 
+// IsSuccess reports if message is sent, i.e. Facebook returned message ID
+func (r FacebookResponse) IsSuccess() bool {
+	return r.MessageID != ""
+}
+
+// String returns response in readable format, used for debugging
+func (r FacebookResponse) String() string {
+	return fmt.Sprintf("FacebookResponse{MessageID: %s, RecipientID: %d}", r.MessageID, r.RecipientID)
+}
+
 // FacebookError received form Facebook server if sending messages failed
 type FacebookError struct {
-	Code      int    `json:"code"`
-	FbtraceID string `json:"fbtrace_id"`
-	Message   string `json:"message"`
-	Type      string `json:"type"`
+	Code         int    `json:"code"`
+	ErrorSubcode int    `json:"error_subcode"`
+	FbtraceID    string `json:"fbtrace_id"`
+	Message      string `json:"message"`
+	Type         string `json:"type"`
 }
 
 // Error returns Go error object constructed from FacebookError data
+// Returned error can be inspected with errors.Is and errors.As, see FacebookAPIError
 func (err *FacebookError) Error() error {
-	return fmt.Errorf("FB Error: Type %s: %s; FB trace ID: %s", err.Type, err.Message, err.FbtraceID)
+	return wrapFacebookError(err)
 }
@@ -0,0 +1,81 @@
+package messenger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// DefaultGetStartedPayload is payload of Get Started button set with SetGetStarted without options
+const DefaultGetStartedPayload = "GET_STARTED"
+
+// GetStartedPayload is structured payload of Get Started button, Source tells where the user came from
+type GetStartedPayload struct {
+	Source string            `json:"source,omitempty"`
+	Data   map[string]string `json:"data,omitempty"`
+}
+
+// Encode returns payload encoded as JSON string, decode it with DecodeGetStartedPayload
+func (p GetStartedPayload) Encode() string {
+	b, _ := json.Marshal(struct {
+		GetStarted GetStartedPayload `json:"get_started"`
+	}{p})
+	return string(b)
+}
+
+// DecodeGetStartedPayload decodes payload encoded with GetStartedPayload.Encode
+func DecodeGetStartedPayload(raw string) (GetStartedPayload, error) {
+	var v struct {
+		GetStarted *GetStartedPayload `json:"get_started"`
+	}
+	if err := json.Unmarshal([]byte(raw), &v); err != nil || v.GetStarted == nil {
+		return GetStartedPayload{}, fmt.Errorf("messenger: invalid get started payload %q", raw)
+	}
+	return *v.GetStarted, nil
+}
+
+// GetStartedOption sets structured payload of Get Started button, see SetGetStarted
+type GetStartedOption func(p *GetStartedPayload)
+
+// WithGetStartedSource sets source of Get Started payload
+func WithGetStartedSource(source string) GetStartedOption {
+	return func(p *GetStartedPayload) {
+		p.Source = source
+	}
+}
+
+// WithGetStartedData sets data of Get Started payload
+func WithGetStartedData(data map[string]string) GetStartedOption {
+	return func(p *GetStartedPayload) {
+		p.Data = data
+	}
+}
+
+// SetGetStarted sets Get Started button. Without options button payload is DefaultGetStartedPayload,
+// with options it is GetStartedPayload, read it from postback with FacebookPostback.GetStarted
+func (msng *Messenger) SetGetStarted(ctx context.Context, opts ...GetStartedOption) error {
+	payload := DefaultGetStartedPayload
+	if len(opts) > 0 {
+		var p GetStartedPayload
+		for _, opt := range opts {
+			opt(&p)
+		}
+		payload = p.Encode()
+	}
+	return msng.setMessengerProfile(ctx, map[string]interface{}{"get_started": GetStarted{Payload: payload}})
+}
+
+// IsGetStarted returns true if postback is sent by Get Started button set with SetGetStarted
+func (p FacebookPostback) IsGetStarted() bool {
+	if p.Payload == DefaultGetStartedPayload {
+		return true
+	}
+	_, ok := p.GetStarted()
+	return ok
+}
+
+// GetStarted returns structured Get Started payload of postback, false if postback has no such payload
+func (p FacebookPostback) GetStarted() (GetStartedPayload, bool) {
+	gp, err := DecodeGetStartedPayload(p.Payload)
+	return gp, err == nil
+}
@@ -0,0 +1,59 @@
+package messenger_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestGetStartedPayload(t *testing.T) {
+	p := messenger.GetStartedPayload{Source: "discover", Data: map[string]string{"campaign": "spring"}}
+	decoded, err := messenger.DecodeGetStartedPayload(p.Encode())
+	if err != nil || !reflect.DeepEqual(decoded, p) {
+		t.Error("Expected", p, "decoded", decoded, err)
+	}
+	if _, err := messenger.DecodeGetStartedPayload(`{"source":"discover"}`); err == nil {
+		t.Error("Expected error for payload without get_started")
+	}
+
+	postbacks := map[string]bool{
+		messenger.DefaultGetStartedPayload: true,
+		p.Encode():                         true,
+		"BUY":                              false,
+	}
+	for payload, expected := range postbacks {
+		if (messenger.FacebookPostback{Payload: payload}).IsGetStarted() != expected {
+			t.Error("Unexpected IsGetStarted for", payload)
+		}
+	}
+}
+
+func TestSetGetStarted(t *testing.T) {
+	fb.Reset()
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "")
+	if err := msng.SetGetStarted(ctx); err != nil {
+		t.Fatal(err)
+	}
+	call, _ := fb.LastCall()
+	if string(call.Body) != `{"get_started":{"payload":"GET_STARTED"}}` {
+		t.Error("Unexpected request", string(call.Body))
+	}
+
+	if err := msng.SetGetStarted(ctx, messenger.WithGetStartedSource("link"), messenger.WithGetStartedData(map[string]string{"ref": "ad1"})); err != nil {
+		t.Fatal(err)
+	}
+	call, _ = fb.LastCall()
+	var body struct {
+		GetStarted messenger.GetStarted `json:"get_started"`
+	}
+	json.Unmarshal(call.Body, &body)
+	gp, ok := messenger.FacebookPostback{Payload: body.GetStarted.Payload}.GetStarted()
+	if !ok || gp.Source != "link" || gp.Data["ref"] != "ad1" {
+		t.Error("Unexpected payload", body.GetStarted.Payload)
+	}
+}
@@ -0,0 +1,90 @@
+package messenger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// graphBaseURL returns Graph API base URL, or mock FB URL when testing
+func graphBaseURL() string {
+	if TestURL != "" {
+		return TestURL // testing, mock FB URL
+	}
+	return apiURL
+}
+
+// baseURL returns Graph API base URL used by messenger, see WithTestURL
+func (msng *Messenger) baseURL() string {
+	if msng.apiURLOverride != "" {
+		return msng.apiURLOverride
+	}
+	if TestURL == "" && msng.APIVersion != "" {
+		return "https://graph.facebook.com/" + msng.APIVersion + "/"
+	}
+	return graphBaseURL()
+}
+
+// graphURL returns Graph API URL for path (without leading slash) with access token added to query
+func (msng *Messenger) graphURL(path string, query url.Values, accessToken string) string {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("access_token", accessToken)
+	return msng.baseURL() + path + "?" + q.Encode()
+}
+
+// graphRequest calls Graph API with messenger's access token. If body is not nil it is sent JSON encoded,
+// if v is not nil response is decoded into v. Errors received from Facebook are returned as error
+func (msng *Messenger) graphRequest(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	token, err := msng.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	return doGraphRequest(ctx, msng.GetClient(), method, msng.graphURL(path, query, token), body, v)
+}
+
+// doGraphRequest calls Graph API URL u with client c, see graphRequest
+func doGraphRequest(ctx context.Context, c *http.Client, method, u string, body, v interface{}) error {
+	var rd io.Reader
+	if body != nil {
+		s, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(s)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, rd)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := responseError(resp.StatusCode, b); err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,97 @@
+package messenger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// Well known Facebook app IDs used in handover protocol
+const (
+	// AppIDPageInbox is Page Inbox app, thread is passed to it when human agent should take over
+	AppIDPageInbox = "263902037430900"
+
+	// AppIDMessengerPlatformRobots is Messenger Platform app used for page inbox automation
+	AppIDMessengerPlatformRobots = "1217981644879628"
+)
+
+// HandoverEvent is received when thread control is passed to or taken from your app
+type HandoverEvent struct {
+	NewOwnerAppID       string `json:"new_owner_app_id"`
+	PreviousOwnerAppID  string `json:"previous_owner_app_id"`
+	RequestedOwnerAppID string `json:"requested_owner_app_id"` // set in request thread control event
+	Metadata            string `json:"metadata"`
+}
+
+// ParseMetadata decodes JSON metadata sent by the app that passed thread control into v.
+// If there is no metadata, v is left unchanged and nil is returned
+func (e HandoverEvent) ParseMetadata(v interface{}) error {
+	if e.Metadata == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(e.Metadata), v)
+}
+
+// IsPageInbox reports whether thread is passed to Page Inbox, i.e. to human agent
+func (e HandoverEvent) IsPageInbox() bool {
+	return e.NewOwnerAppID == AppIDPageInbox
+}
+
+// GetThreadOwner returns app ID of the app that currently owns the thread with user
+func (msng *Messenger) GetThreadOwner(ctx context.Context, userID string) (string, error) {
+	var reply struct {
+		Data []struct {
+			ThreadOwner struct {
+				AppID string `json:"app_id"`
+			} `json:"thread_owner"`
+		} `json:"data"`
+	}
+	q := url.Values{"recipient": {userID}}
+	if err := msng.graphRequest(ctx, http.MethodGet, "me/thread_owner", q, nil, &reply); err != nil {
+		return "", err
+	}
+	if len(reply.Data) == 0 {
+		return "", nil
+	}
+	return reply.Data[0].ThreadOwner.AppID, nil
+}
+
+// IsThreadOwner checks if your app owns the thread with user, so it is safe to send messages.
+// If human agent or other app owns the thread, you should not send messages to the user
+func (msng *Messenger) IsThreadOwner(ctx context.Context, userID string) (bool, error) {
+	p, err := msng.cachedPageInfo(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	owner, err := msng.GetThreadOwner(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return owner == p.AppID, nil
+}
+
+// RequestThreadControl asks primary receiver app to pass thread control with recipientID to your app,
+// used by secondary receivers. Metadata is sent to primary receiver in request thread control event
+func (msng *Messenger) RequestThreadControl(ctx context.Context, recipientID string, metadata string) error {
+	body := struct {
+		Recipient recipient `json:"recipient"`
+		Metadata  string    `json:"metadata,omitempty"`
+	}{newRecipient(recipientID), metadata}
+	return msng.graphRequest(ctx, http.MethodPost, "me/request_thread_control", nil, body, nil)
+}
+
+// SecondaryReceiver is app that can receive thread control as secondary receiver
+type SecondaryReceiver struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetSecondaryReceivers returns apps set as secondary receivers for the page
+func (msng *Messenger) GetSecondaryReceivers(ctx context.Context) ([]SecondaryReceiver, error) {
+	q := url.Values{"fields": {"id,name"}}
+	return FetchAll(ctx, func(after string) (PagedResponse[SecondaryReceiver], error) {
+		return fetchGraphPage[SecondaryReceiver](ctx, msng, "me/secondary_receivers", q, after)
+	}, 0)
+}
@@ -0,0 +1,133 @@
+package messenger_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestPassThreadControl(t *testing.T) {
+	received := make(chan messenger.HandoverEvent, 1)
+	msng := messenger.New("XXXXXXX", "1")
+	msng.PassThreadControlReceived = func(msng *messenger.Messenger, userID int64, e messenger.HandoverEvent) {
+		received <- e
+	}
+
+	event := `{"object":"page","entry":[{"id":1,"time":1458692752478,"messaging":[{"sender":{"id":"12123213123"},"recipient":{"id":"1"},` +
+		`"timestamp":1458692752478,"pass_thread_control":{"new_owner_app_id":"263902037430900","previous_owner_app_id":"42","metadata":"{\"reason\":\"help\"}"}}]}]}`
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(event)))
+
+	e := <-received
+	if !e.IsPageInbox() || e.PreviousOwnerAppID != "42" {
+		t.Error("Unexpected handover event", e)
+	}
+
+	var metadata struct {
+		Reason string `json:"reason"`
+	}
+	if err := e.ParseMetadata(&metadata); err != nil || metadata.Reason != "help" {
+		t.Error("Unexpected metadata", metadata, err)
+	}
+}
+
+func TestParseEmptyMetadata(t *testing.T) {
+	var metadata struct {
+		Reason string `json:"reason"`
+	}
+	if err := (messenger.HandoverEvent{}).ParseMetadata(&metadata); err != nil || metadata.Reason != "" {
+		t.Error("Expected zero value for empty metadata", metadata, err)
+	}
+	if err := (messenger.HandoverEvent{Metadata: "not json"}).ParseMetadata(&metadata); err == nil {
+		t.Error("Expected error for invalid metadata")
+	}
+}
+
+func TestGetSecondaryReceivers(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/secondary_receivers" {
+			t.Error("Unexpected path", r.URL.Path)
+		}
+		if r.FormValue("after") == "" {
+			w.Write([]byte(`{"data":[{"id":"1","name":"Inbox"}],"paging":{"cursors":{"after":"A"},"next":"https://graph.facebook.com/next"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"2","name":"CRM"}],"paging":{"cursors":{"before":"A"}}}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	receivers, err := msng.GetSecondaryReceivers(context.Background())
+	if err != nil || len(receivers) != 2 || receivers[1].Name != "CRM" {
+		t.Error("Unexpected receivers", receivers, err)
+	}
+}
+
+func TestRequestThreadControl(t *testing.T) {
+	received := make(chan messenger.HandoverEvent, 1)
+	msng := messenger.New("XXXXXXX", "1")
+	msng.RequestThreadControlReceived = func(msng *messenger.Messenger, userID int64, e messenger.HandoverEvent) {
+		received <- e
+	}
+
+	event := `{"object":"page","entry":[{"id":1,"time":1458692752478,"messaging":[{"sender":{"id":"12123213123"},"recipient":{"id":"1"},` +
+		`"timestamp":1458692752478,"request_thread_control":{"requested_owner_app_id":"123","metadata":"agent"}}]}]}`
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(event)))
+	if e := <-received; e.RequestedOwnerAppID != "123" || e.Metadata != "agent" {
+		t.Error("Unexpected request thread control event", e)
+	}
+
+	fb.Reset()
+	fb.RespondWith("me/request_thread_control", http.StatusOK, map[string]bool{"success": true})
+	if err := msng.RequestThreadControl(context.Background(), "12123213123", "agent"); err != nil {
+		t.Fatal(err)
+	}
+	call, _ := fb.LastCall()
+	if call.Method != http.MethodPost || call.Endpoint != "me/request_thread_control" || !strings.Contains(string(call.Body), `"metadata":"agent"`) {
+		t.Error("Unexpected request", call.Method, call.Endpoint, string(call.Body))
+	}
+}
+
+func TestIsThreadOwner(t *testing.T) {
+	t.Parallel()
+	var pageInfoCalls int
+	owner := "42"
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/me":
+			pageInfoCalls++
+			w.Write([]byte(`{"id":"1","name":"Pizza Bot"}`))
+		case "/app":
+			w.Write([]byte(`{"id":"42"}`))
+		case "/me/thread_owner":
+			if r.FormValue("recipient") != "12123213123" {
+				t.Error("Unexpected recipient", r.FormValue("recipient"))
+			}
+			w.Write([]byte(`{"data":[{"thread_owner":{"app_id":"` + owner + `"}}]}`))
+		default:
+			t.Error("Unexpected path", r.URL.Path)
+		}
+	})
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "1", mock)
+	if ok, err := msng.IsThreadOwner(ctx, "12123213123"); err != nil || !ok {
+		t.Error("Expected app to own the thread", ok, err)
+	}
+
+	owner = "263902037430900" // page inbox
+	if ok, err := msng.IsThreadOwner(ctx, "12123213123"); err != nil || ok {
+		t.Error("Expected page inbox to own the thread", ok, err)
+	}
+	if pageInfoCalls != 1 {
+		t.Error("Expected page info fetched once, fetched", pageInfoCalls)
+	}
+
+	info, err := msng.GetPageInfo(ctx)
+	if err != nil || info.ID != "1" || info.Name != "Pizza Bot" || info.AppID != "42" {
+		t.Error("Unexpected page info", info, err)
+	}
+}
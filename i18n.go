@@ -0,0 +1,71 @@
+package messenger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Localizer holds message templates for locales, see SendLocalizedText
+type Localizer struct {
+	defaultLocale string
+	messages      map[string]map[string]string // locale -> key -> fmt template
+}
+
+// NewLocalizer creates localizer with messages keyed by locale (like en_US, or just en) and message key.
+// Messages are fmt templates, missing messages fall back to defaultLocale
+func NewLocalizer(defaultLocale string, messages map[string]map[string]string) *Localizer {
+	return &Localizer{defaultLocale: defaultLocale, messages: messages}
+}
+
+// WithLocalizer sets localizer used by SendLocalizedText
+func WithLocalizer(l *Localizer) Option {
+	return func(msng *Messenger) {
+		msng.Localizer = l
+	}
+}
+
+// Get returns message key for locale formatted with args. If message is missing for locale
+// its language (en for en_US) and default locale are tried, key is returned if message is missing there too
+func (l *Localizer) Get(locale, key string, args ...interface{}) string {
+	s, ok := l.lookup(locale, key, args)
+	if !ok {
+		return key
+	}
+	return s
+}
+
+func (l *Localizer) lookup(locale, key string, args []interface{}) (string, bool) {
+	locales := []string{locale}
+	if i := strings.IndexByte(locale, '_'); i > 0 {
+		locales = append(locales, locale[:i])
+	}
+	locales = append(locales, l.defaultLocale)
+
+	for _, loc := range locales {
+		if tmpl, ok := l.messages[loc][key]; ok {
+			if len(args) == 0 {
+				return tmpl, true
+			}
+			return fmt.Sprintf(tmpl, args...), true
+		}
+	}
+	return "", false
+}
+
+// SendLocalizedText sends message key translated to user's locale from user profile.
+// ErrMissingTranslation is returned if message is missing for user's locale and for default locale
+func (msng *Messenger) SendLocalizedText(ctx context.Context, userID string, key string, args ...interface{}) (FacebookResponse, error) {
+	if msng.Localizer == nil {
+		return FacebookResponse{}, ErrNoLocalizer
+	}
+	p, err := msng.GetUserProfileFields(ctx, userID, ProfileFieldLocale)
+	if err != nil {
+		return FacebookResponse{}, err
+	}
+	text, ok := msng.Localizer.lookup(p.Locale, key, args)
+	if !ok {
+		return FacebookResponse{}, fmt.Errorf("%w: %q for locale %s", ErrMissingTranslation, key, p.Locale)
+	}
+	return msng.SendTextMessageStr(ctx, userID, text)
+}
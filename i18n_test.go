@@ -0,0 +1,71 @@
+package messenger_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+var translations = map[string]map[string]string{
+	"en_US": {"welcome": "Welcome, %s!", "bye": "Bye"},
+	"de":    {"welcome": "Willkommen, %s!"},
+}
+
+func TestLocalizer(t *testing.T) {
+	l := messenger.NewLocalizer("en_US", translations)
+
+	tests := []struct {
+		locale, key, want string
+	}{
+		{"en_US", "welcome", "Welcome, Ana!"},
+		{"de_DE", "welcome", "Willkommen, Ana!"}, // language fallback
+		{"fr_FR", "welcome", "Welcome, Ana!"},    // default locale fallback
+	}
+	for _, test := range tests {
+		if got := l.Get(test.locale, test.key, "Ana"); got != test.want {
+			t.Errorf("%s %s: expected %q, returned %q", test.locale, test.key, test.want, got)
+		}
+	}
+
+	if got := l.Get("de_DE", "bye"); got != "Bye" {
+		t.Error("Expected Bye, returned", got)
+	}
+	if got := l.Get("fr_FR", "missing"); got != "missing" {
+		t.Error("Expected key for missing message, returned", got)
+	}
+}
+
+func TestSendLocalizedText(t *testing.T) {
+	t.Parallel()
+	var text string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/1254477777772919" {
+			w.Write([]byte(`{"id":"1254477777772919","locale":"de_DE"}`))
+			return
+		}
+		var body struct {
+			Message struct {
+				Text string `json:"text"`
+			} `json:"message"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		text = body.Message.Text
+		w.Write([]byte(`{"recipient_id":"1254477777772919","message_id":"mid.1"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock, messenger.WithLocalizer(messenger.NewLocalizer("en_US", translations)))
+	if _, err := msng.SendLocalizedText(context.Background(), "1254477777772919", "welcome", "Ana"); err != nil {
+		t.Fatal(err)
+	}
+	if text != "Willkommen, Ana!" {
+		t.Error("Expected german welcome, sent", text)
+	}
+
+	if _, err := msng.SendLocalizedText(context.Background(), "1254477777772919", "missing"); !errors.Is(err, messenger.ErrMissingTranslation) {
+		t.Error("Expected ErrMissingTranslation, returned", err)
+	}
+}
@@ -0,0 +1,99 @@
+package messenger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// graphTimeLayout is format of times returned by Graph API, e.g. 2017-03-20T12:00:00+0000
+const graphTimeLayout = "2006-01-02T15:04:05-0700"
+
+// InboxFolder is folder of page inbox, see GetInboxThreads
+type InboxFolder string
+
+const (
+	// InboxFolderInbox is folder with open threads
+	InboxFolderInbox = InboxFolder("INBOX")
+
+	// InboxFolderDone is folder with threads marked as done
+	InboxFolderDone = InboxFolder("DONE")
+
+	// InboxFolderSpam is folder with threads marked as spam
+	InboxFolderSpam = InboxFolder("SPAM")
+)
+
+// ThreadParticipant is user or page participating in inbox thread
+type ThreadParticipant struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// InboxThread is conversation thread in page inbox
+type InboxThread struct {
+	ID           string
+	Snippet      string
+	UpdatedTime  time.Time
+	MessageCount int
+	Participants []ThreadParticipant
+}
+
+// ThreadsPage is page of inbox threads, pass its NextCursor to GetInboxThreads to get next page
+type ThreadsPage = PagedResponse[InboxThread]
+
+// UnmarshalJSON decodes thread received from Graph API
+func (t *InboxThread) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		ID           string `json:"id"`
+		Snippet      string `json:"snippet"`
+		UpdatedTime  string `json:"updated_time"`
+		MessageCount int    `json:"message_count"`
+		Participants struct {
+			Data []ThreadParticipant `json:"data"`
+		} `json:"participants"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*t = InboxThread{
+		ID:           raw.ID,
+		Snippet:      raw.Snippet,
+		MessageCount: raw.MessageCount,
+		Participants: raw.Participants.Data,
+	}
+	if raw.UpdatedTime != "" {
+		updated, err := time.Parse(graphTimeLayout, raw.UpdatedTime)
+		if err != nil {
+			return err
+		}
+		t.UpdatedTime = updated
+	}
+	return nil
+}
+
+// GetInboxThreads returns page of threads in inbox folder, cursor is "" for the first page
+func (msng *Messenger) GetInboxThreads(ctx context.Context, folder InboxFolder, cursor string) (ThreadsPage, error) {
+	q := url.Values{
+		"folder": {string(folder)},
+		"fields": {"id,snippet,updated_time,message_count,participants"},
+	}
+	return fetchGraphPage[InboxThread](ctx, msng, "me/conversations", q, cursor)
+}
+
+// MarkThreadDone moves inbox thread to done folder
+func (msng *Messenger) MarkThreadDone(ctx context.Context, threadID string) error {
+	return msng.setInboxLabel(ctx, threadID, "DONE")
+}
+
+// MarkThreadUnread marks inbox thread as unread
+func (msng *Messenger) MarkThreadUnread(ctx context.Context, threadID string) error {
+	return msng.setInboxLabel(ctx, threadID, "UNREAD")
+}
+
+func (msng *Messenger) setInboxLabel(ctx context.Context, threadID, label string) error {
+	q := url.Values{"inbox_labels": {`["` + label + `"]`}}
+	return msng.graphRequest(ctx, http.MethodPost, url.PathEscape(threadID), q, nil, nil)
+}
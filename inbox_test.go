@@ -0,0 +1,59 @@
+package messenger_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestGetInboxThreads(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/conversations" || r.FormValue("folder") != "DONE" || r.FormValue("after") != "CURSOR" {
+			t.Error("Unexpected request", r.URL)
+		}
+		w.Write([]byte(`{"data":[{"id":"t_1","snippet":"Thanks!","updated_time":"2017-03-20T12:30:00+0000","message_count":4,
+			"participants":{"data":[{"id":"42","name":"John Doe","email":"42@facebook.com"}]}}],
+			"paging":{"cursors":{"after":"NEXT"},"next":"https://graph.facebook.com/next"}}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	page, err := msng.GetInboxThreads(context.Background(), messenger.InboxFolderDone, "CURSOR")
+	if err != nil || len(page.Data) != 1 || page.NextCursor() != "NEXT" {
+		t.Fatal("Unexpected page", page, err)
+	}
+	thread := page.Data[0]
+	if thread.ID != "t_1" || thread.MessageCount != 4 || len(thread.Participants) != 1 || thread.Participants[0].Name != "John Doe" {
+		t.Error("Unexpected thread", thread)
+	}
+	if !thread.UpdatedTime.Equal(time.Date(2017, 3, 20, 12, 30, 0, 0, time.UTC)) {
+		t.Error("Unexpected updated time", thread.UpdatedTime)
+	}
+}
+
+func TestMarkThread(t *testing.T) {
+	t.Parallel()
+	var labels []string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/t_1" {
+			t.Error("Unexpected request", r.Method, r.URL)
+		}
+		labels = append(labels, r.URL.Query().Get("inbox_labels"))
+		w.Write([]byte(`{"success":true}`))
+	})
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "", mock)
+	if err := msng.MarkThreadDone(ctx, "t_1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := msng.MarkThreadUnread(ctx, "t_1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(labels) != 2 || labels[0] != `["DONE"]` || labels[1] != `["UNREAD"]` {
+		t.Error("Unexpected labels", labels)
+	}
+}
@@ -0,0 +1,39 @@
+package messenger
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// SendPrivateReplyToInstagramComment sends private message reply to Instagram comment on page linked Instagram account
+func (msng *Messenger) SendPrivateReplyToInstagramComment(ctx context.Context, commentID string, text string) (FacebookResponse, error) {
+	m := struct {
+		Recipient struct {
+			CommentID string `json:"comment_id"`
+		} `json:"recipient"`
+		Message textMessageContent `json:"message"`
+	}{}
+	m.Recipient.CommentID = commentID
+	m.Message.Text = text
+
+	var resp rawFBResponse
+	if err := msng.graphRequest(ctx, http.MethodPost, "me/messages", nil, m, &resp); err != nil {
+		return FacebookResponse{}, err
+	}
+	return FacebookResponse{MessageID: resp.MessageID, RecipientID: resp.RecipientID}, nil
+}
+
+// IsInstagramCommentID reports whether id looks like Instagram comment ID rather than Messenger PSID.
+// Instagram media and comment IDs are 17 or 18 digits long starting with 17 or 18, PSIDs are 16 digits long
+func IsInstagramCommentID(id string) bool {
+	if len(id) != 17 && len(id) != 18 {
+		return false
+	}
+	for _, c := range id {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return strings.HasPrefix(id, "17") || strings.HasPrefix(id, "18")
+}
@@ -0,0 +1,46 @@
+package messenger_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestSendPrivateReplyToInstagramComment(t *testing.T) {
+	t.Parallel()
+	var body string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"recipient_id":"1254477777772919","message_id":"mid.1"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	resp, err := msng.SendPrivateReplyToInstagramComment(context.Background(), "17895695668004550", "thanks")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"recipient":{"comment_id":"17895695668004550"},"message":{"text":"thanks"}}`
+	if body != expected {
+		t.Error("Expected", expected, "sent", body)
+	}
+	if resp.MessageID != "mid.1" {
+		t.Error("Unexpected response", resp)
+	}
+}
+
+func TestIsInstagramCommentID(t *testing.T) {
+	if !messenger.IsInstagramCommentID("17895695668004550") {
+		t.Error("Expected Instagram comment ID")
+	}
+	if messenger.IsInstagramCommentID("1254477777772919") {
+		t.Error("PSID is not Instagram comment ID")
+	}
+	if messenger.IsInstagramCommentID("1789569566800455x") {
+		t.Error("Non numeric ID is not Instagram comment ID")
+	}
+}
@@ -0,0 +1,27 @@
+//go:build go1.23
+
+package messenger
+
+import "iter"
+
+// Entries returns iterator over entries of request
+func (r FacebookRequest) Entries() iter.Seq[Entry] {
+	return func(yield func(Entry) bool) {
+		for _, entry := range r.Entry {
+			if !yield(entry) {
+				return
+			}
+		}
+	}
+}
+
+// MessagingEvents returns iterator over messaging events of entry, standby events are not included
+func (e Entry) MessagingEvents() iter.Seq[MessagingEntry] {
+	return func(yield func(MessagingEntry) bool) {
+		for _, msg := range e.Messaging {
+			if !yield(msg) {
+				return
+			}
+		}
+	}
+}
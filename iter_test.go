@@ -0,0 +1,40 @@
+//go:build go1.23
+
+package messenger_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestRequestIterators(t *testing.T) {
+	req, err := messenger.DecodeRequest(httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ranged []messenger.EventType
+	for entry := range req.Entries() {
+		for event := range entry.MessagingEvents() {
+			ranged = append(ranged, event.EventType())
+		}
+	}
+	var called []messenger.EventType
+	req.ForEach(func(event messenger.MessagingEntry) {
+		called = append(called, event.EventType())
+	})
+
+	if len(ranged) != 2 || ranged[0] != messenger.EventTypeMessage || ranged[1] != messenger.EventTypeRead {
+		t.Error("Unexpected events", ranged)
+	}
+	if len(called) != len(ranged) || called[0] != ranged[0] || called[1] != ranged[1] {
+		t.Error("Expected ForEach to visit", ranged, "visited", called)
+	}
+
+	for range req.Entries() {
+		break // iterator stops early
+	}
+}
@@ -0,0 +1,81 @@
+package messenger
+
+import "strings"
+
+type keywordRoute struct {
+	keyword string
+	handler MessageHandler
+}
+
+// KeywordHandler routes received text messages to handlers by keywords. Exact matches are checked first,
+// then case-insensitive matches and finally keywords contained in the text, in order they were added
+type KeywordHandler struct {
+	exact    map[string]MessageHandler
+	fold     map[string]MessageHandler
+	contains []keywordRoute
+	fallback MessageHandler
+}
+
+// NewKeywordHandler creates empty KeywordHandler
+func NewKeywordHandler() *KeywordHandler {
+	return &KeywordHandler{exact: map[string]MessageHandler{}, fold: map[string]MessageHandler{}}
+}
+
+// Handle routes messages with text equal to keyword to handler. Leading and trailing spaces are ignored
+func (kh *KeywordHandler) Handle(keyword string, handler MessageHandler) *KeywordHandler {
+	kh.exact[keyword] = handler
+	return kh
+}
+
+// HandleFold routes messages with text equal to keyword, ignoring case, to handler
+func (kh *KeywordHandler) HandleFold(keyword string, handler MessageHandler) *KeywordHandler {
+	kh.fold[strings.ToLower(keyword)] = handler
+	return kh
+}
+
+// HandleContains routes messages containing keyword, ignoring case, to handler
+func (kh *KeywordHandler) HandleContains(keyword string, handler MessageHandler) *KeywordHandler {
+	kh.contains = append(kh.contains, keywordRoute{strings.ToLower(keyword), handler})
+	return kh
+}
+
+// Default sets handler for messages that don't match any keyword
+func (kh *KeywordHandler) Default(handler MessageHandler) *KeywordHandler {
+	kh.fallback = handler
+	return kh
+}
+
+// MessageHandler returns handler that routes messages, set it as MessageReceived or use Messenger.SetKeywordHandler
+func (kh *KeywordHandler) MessageHandler() func(msng *Messenger, userID int64, m FacebookMessage) {
+	return func(msng *Messenger, userID int64, m FacebookMessage) {
+		if h := kh.route(m.Text); h != nil {
+			h(msng, userID, m)
+		}
+	}
+}
+
+func (kh *KeywordHandler) route(text string) MessageHandler {
+	text = strings.TrimSpace(text)
+	if h, ok := kh.exact[text]; ok {
+		return h
+	}
+	lower := strings.ToLower(text)
+	if h, ok := kh.fold[lower]; ok {
+		return h
+	}
+	for _, r := range kh.contains {
+		if strings.Contains(lower, r.keyword) {
+			return r.handler
+		}
+	}
+	return kh.fallback
+}
+
+// SetKeywordHandler sets kh as MessageReceived handler. If kh has no default handler,
+// existing MessageReceived handler is used for messages that don't match any keyword
+func (msng *Messenger) SetKeywordHandler(kh *KeywordHandler) {
+	if kh.fallback == nil && msng.MessageReceived != nil {
+		kh.fallback = msng.MessageReceived
+	}
+	msng.MessageReceived = kh.MessageHandler()
+}
@@ -0,0 +1,41 @@
+package messenger_test
+
+import (
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestKeywordHandler(t *testing.T) {
+	var routed string
+	handler := func(name string) messenger.MessageHandler {
+		return func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) {
+			routed = name
+		}
+	}
+
+	msng := messenger.New("XXXXXXX", "")
+	msng.MessageReceived = handler("previous")
+	msng.SetKeywordHandler(messenger.NewKeywordHandler().
+		Handle("HELP", handler("exact")).
+		HandleFold("help", handler("fold")).
+		HandleContains("stop", handler("contains")))
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"HELP", "exact"},
+		{" HELP ", "exact"},
+		{"Help", "fold"},
+		{"please STOP it", "contains"},
+		{"hello", "previous"},
+	}
+	for _, test := range tests {
+		routed = ""
+		msng.MessageReceived(&msng, 1, messenger.FacebookMessage{Text: test.text})
+		if routed != test.want {
+			t.Errorf("Text %q: expected %s, routed to %q", test.text, test.want, routed)
+		}
+	}
+}
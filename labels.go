@@ -0,0 +1,33 @@
+package messenger
+
+import (
+	"context"
+	"net/url"
+)
+
+type labelUser struct {
+	ID string `json:"id"`
+}
+
+// GetPSIDsForLabel returns PSIDs of all users associated with custom label
+func (msng *Messenger) GetPSIDsForLabel(ctx context.Context, labelID string) ([]string, error) {
+	users, err := fetchAllGraphPages[labelUser](ctx, msng, url.PathEscape(labelID)+"/label", nil)
+	return labelUserIDs(users), err
+}
+
+// IteratePSIDsForLabel returns iterator of PSIDs of users associated with custom label
+func (msng *Messenger) IteratePSIDsForLabel(labelID string) *PageIterator[string] {
+	fetch := graphPageFetcher[labelUser](msng, url.PathEscape(labelID)+"/label", nil)
+	return NewPageIterator(func(ctx context.Context, cursor string) ([]string, string, error) {
+		users, next, err := fetch(ctx, cursor)
+		return labelUserIDs(users), next, err
+	})
+}
+
+func labelUserIDs(users []labelUser) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return ids
+}
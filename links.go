@@ -0,0 +1,42 @@
+package messenger
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+const meMeURL = "https://m.me/"
+
+// GenerateMeMeLink returns m.me link that opens conversation with page, ref is sent back in referral event
+func GenerateMeMeLink(pageUsername string, ref string) string {
+	return meMeLink(url.PathEscape(pageUsername), ref)
+}
+
+// GenerateMeMeGroupLink returns m.me link for group bots
+func GenerateMeMeGroupLink(groupUsername, ref string) string {
+	return meMeLink("j/"+url.PathEscape(groupUsername), ref)
+}
+
+func meMeLink(path, ref string) string {
+	if ref == "" {
+		return meMeURL + path
+	}
+	return meMeURL + path + "?ref=" + url.QueryEscape(ref)
+}
+
+// ParseMeMeRef returns ref parameter from m.me link
+func ParseMeMeRef(rawURL string) (ref string, err error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL // m.me links are often written without scheme
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Host != "m.me" && u.Host != "www.m.me" {
+		return "", errors.New("messenger: not m.me link: " + rawURL)
+	}
+	return u.Query().Get("ref"), nil
+}
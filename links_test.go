@@ -0,0 +1,48 @@
+package messenger_test
+
+import (
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestMeMeLink(t *testing.T) {
+	tests := []struct {
+		page, ref, link string
+	}{
+		{"jasper.s.cafe", "", "https://m.me/jasper.s.cafe"},
+		{"jasper.s.cafe", "billboard-ad", "https://m.me/jasper.s.cafe?ref=billboard-ad"},
+		{"mybot", "source=site&campaign=spring sale", "https://m.me/mybot?ref=source%3Dsite%26campaign%3Dspring+sale"},
+		{"mybot", "a+b/c=d:e_f.g", "https://m.me/mybot?ref=a%2Bb%2Fc%3Dd%3Ae_f.g"},
+	}
+
+	for _, tt := range tests {
+		link := messenger.GenerateMeMeLink(tt.page, tt.ref)
+		if link != tt.link {
+			t.Error("Expected", tt.link, "returned", link)
+		}
+
+		ref, err := messenger.ParseMeMeRef(link)
+		if err != nil || ref != tt.ref {
+			t.Error("Expected ref", tt.ref, "returned", ref, err)
+		}
+	}
+}
+
+func TestMeMeGroupLink(t *testing.T) {
+	link := messenger.GenerateMeMeGroupLink("AbCdEf", "group-ref")
+	if link != "https://m.me/j/AbCdEf?ref=group-ref" {
+		t.Error("Unexpected group link", link)
+	}
+}
+
+func TestParseMeMeRef(t *testing.T) {
+	ref, err := messenger.ParseMeMeRef("m.me/jasper.s.cafe?ref=billboard-ad")
+	if err != nil || ref != "billboard-ad" {
+		t.Error("Expected billboard-ad, returned", ref, err)
+	}
+
+	if _, err := messenger.ParseMeMeRef("https://example.com/?ref=x"); err == nil {
+		t.Error("Expected error for non m.me link")
+	}
+}
@@ -0,0 +1,118 @@
+package messenger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultLockIdleTimeout is time after which unused user locks are removed from ConversationLock
+const DefaultLockIdleTimeout = 10 * time.Minute
+
+// ConversationLock holds lock per user so events of the same user can be processed one at a time,
+// in order the lock was requested. Locks are created on demand and removed when not used for idle timeout
+type ConversationLock struct {
+	idleTimeout time.Duration
+	locks       sync.Map // userID -> *userLock
+	lastCleanup atomic.Int64
+}
+
+type userLock struct {
+	mu       sync.Mutex  // guards fields below
+	queue    []*lockTurn // holder of the lock first, then waiters in order
+	lastUsed time.Time
+	removed  bool
+}
+
+// lockTurn is place of one lock request in user queue, ready is closed when it is its turn to hold the lock
+type lockTurn struct {
+	ready  chan struct{}
+	unlock func()
+}
+
+// NewConversationLock creates ConversationLock, user locks not used for idleTimeout are removed.
+// DefaultLockIdleTimeout is used if idleTimeout is 0
+func NewConversationLock(idleTimeout time.Duration) *ConversationLock {
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultLockIdleTimeout
+	}
+	cl := &ConversationLock{idleTimeout: idleTimeout}
+	cl.lastCleanup.Store(time.Now().UnixNano())
+	return cl
+}
+
+// WithConversationLock makes messenger run event handlers of the same user one at a time,
+// in order events were received on webhook
+func WithConversationLock() Option {
+	return func(msng *Messenger) {
+		msng.ConversationLock = NewConversationLock(0)
+	}
+}
+
+// Lock locks userID and returns unlock function. Waiting callers get the lock in order they called Lock
+func (cl *ConversationLock) Lock(userID string) (unlock func()) {
+	t := cl.enqueue(userID)
+	<-t.ready
+	return t.unlock
+}
+
+// enqueue adds lock request to the end of user queue without waiting for it
+func (cl *ConversationLock) enqueue(userID string) *lockTurn {
+	cl.cleanup()
+	for {
+		v, _ := cl.locks.LoadOrStore(userID, &userLock{})
+		l := v.(*userLock)
+		l.mu.Lock()
+		if l.removed {
+			l.mu.Unlock()
+			continue
+		}
+		t := &lockTurn{ready: make(chan struct{})}
+		l.queue = append(l.queue, t)
+		if len(l.queue) == 1 {
+			close(t.ready)
+		}
+		l.mu.Unlock()
+
+		var once sync.Once
+		t.unlock = func() {
+			once.Do(func() { l.release(t) })
+		}
+		return t
+	}
+}
+
+// release removes t from the queue, if t held the lock next in the queue gets it
+func (l *userLock) release(t *lockTurn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, qt := range l.queue {
+		if qt == t {
+			l.queue = append(l.queue[:i], l.queue[i+1:]...)
+			if i == 0 && len(l.queue) > 0 {
+				close(l.queue[0].ready)
+			}
+			break
+		}
+	}
+	l.lastUsed = time.Now()
+}
+
+// cleanup removes locks not used for idle timeout, at most once per idle timeout
+func (cl *ConversationLock) cleanup() {
+	now := time.Now()
+	last := cl.lastCleanup.Load()
+	if now.Sub(time.Unix(0, last)) < cl.idleTimeout || !cl.lastCleanup.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	cl.locks.Range(func(k, v interface{}) bool {
+		l := v.(*userLock)
+		l.mu.Lock()
+		if len(l.queue) == 0 && now.Sub(l.lastUsed) >= cl.idleTimeout {
+			l.removed = true
+			cl.locks.Delete(k)
+		}
+		l.mu.Unlock()
+		return true
+	})
+}
@@ -0,0 +1,124 @@
+package messenger_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestConversationLock(t *testing.T) {
+	cl := messenger.NewConversationLock(time.Millisecond)
+
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := cl.Lock("1")
+			defer unlock()
+			n := atomic.AddInt32(&running, 1)
+			if n > atomic.LoadInt32(&maxRunning) {
+				atomic.StoreInt32(&maxRunning, n)
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}()
+	}
+	wg.Wait()
+	if maxRunning != 1 {
+		t.Error("Expected one goroutine holding the lock, max", maxRunning)
+	}
+
+	// idle locks are removed, user can be locked again
+	time.Sleep(5 * time.Millisecond)
+	unlock := cl.Lock("1")
+	unlock()
+	unlock() // calling unlock again has no effect
+}
+
+func TestWithConversationLock(t *testing.T) {
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+	wg.Add(2)
+	handler := func() {
+		defer wg.Done()
+		n := atomic.AddInt32(&running, 1)
+		if n > atomic.LoadInt32(&maxRunning) {
+			atomic.StoreInt32(&maxRunning, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	}
+
+	msng := messenger.New("XXXXXXX", "1", messenger.WithConversationLock())
+	msng.MessageReceived = func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) { handler() }
+	msng.ReadReceived = func(msng *messenger.Messenger, userID int64, r messenger.FacebookRead) { handler() }
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage)))
+	wg.Wait()
+
+	if maxRunning != 1 {
+		t.Error("Expected handlers of the same user to run one at a time, max", maxRunning)
+	}
+}
+
+func TestWithConversationLockOrder(t *testing.T) {
+	var mu sync.Mutex
+	var mids []string
+	var wg sync.WaitGroup
+	msng := messenger.New("XXXXXXX", "1", messenger.WithConversationLock())
+	msng.MessageReceived = func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) {
+		defer wg.Done()
+		mu.Lock()
+		mids = append(mids, m.Mid)
+		mu.Unlock()
+	}
+
+	var events, want []string
+	for i := 0; i < 50; i++ {
+		events = append(events, fmt.Sprintf(`{"sender":{"id":"1"},"recipient":{"id":"1"},"timestamp":1,"message":{"mid":"m%d","text":"hi"}}`, i))
+		want = append(want, fmt.Sprintf("m%d", i))
+	}
+	wg.Add(len(events))
+	body := `{"object":"page","entry":[{"id":1,"time":1,"messaging":[` + strings.Join(events, ",") + `]}]}`
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	wg.Wait()
+
+	if strings.Join(mids, ",") != strings.Join(want, ",") {
+		t.Error("Expected handlers to run in order events were received, got", mids)
+	}
+}
+
+func TestWithConversationLockReleasesHandlerSlot(t *testing.T) {
+	otherDone := make(chan struct{})
+	var wg sync.WaitGroup
+	msng := messenger.New("XXXXXXX", "1", messenger.WithConversationLock(), messenger.WithMaxConcurrentHandlers(2))
+	msng.MessageReceived = func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) {
+		defer wg.Done()
+		switch m.Mid {
+		case "a1":
+			// busy user holds the lock until handler of other user is done
+			select {
+			case <-otherDone:
+			case <-time.After(time.Second):
+				t.Error("Expected handler of other user to run while busy user is locked")
+			}
+		case "b1":
+			close(otherDone)
+		}
+	}
+
+	wg.Add(3)
+	body := `{"object":"page","entry":[{"id":1,"time":1,"messaging":[` +
+		`{"sender":{"id":"1"},"recipient":{"id":"1"},"timestamp":1,"message":{"mid":"a1","text":"hi"}},` +
+		`{"sender":{"id":"1"},"recipient":{"id":"1"},"timestamp":1,"message":{"mid":"a2","text":"hi"}},` +
+		`{"sender":{"id":"2"},"recipient":{"id":"1"},"timestamp":1,"message":{"mid":"b1","text":"hi"}}]}]}`
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	wg.Wait()
+}
@@ -0,0 +1,71 @@
+package messenger
+
+import "errors"
+
+// Logger is used by Messenger for logging sent messages, errors and received webhook events.
+// keyvals are alternating keys and values, like in log/slog. Set it with WithLogger
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// WithLogger sets logger used by messenger
+func WithLogger(l Logger) Option {
+	return func(msng *Messenger) {
+		msng.Logger = l
+	}
+}
+
+// logger returns Logger, or slog.Default() if Logger is not set
+func (msng *Messenger) logger() Logger {
+	if msng.Logger == nil {
+		return NewSlogLogger(nil)
+	}
+	return msng.Logger
+}
+
+// logSend logs result of sending message m
+func (msng *Messenger) logSend(m Message, resp FacebookResponse, err error) {
+	recipientID, messageType := messageInfo(m)
+	if err != nil {
+		code := 0
+		var apiErr FacebookAPIError
+		if errors.As(err, &apiErr) {
+			code = apiErr.Code
+		}
+		msng.logger().Error("send message failed", "recipient_id", recipientID, "message_type", messageType, "code", code, "error", err)
+		return
+	}
+	msng.logger().Debug("message sent", "recipient_id", recipientID, "message_type", messageType, "response_message_id", resp.MessageID)
+}
+
+// logEvent logs webhook event received from Facebook
+func (msng *Messenger) logEvent(eventType string, senderID int64) {
+	msng.logger().Info("webhook event received", "event_type", eventType, "sender_id", senderID)
+}
+
+// messageInfo returns recipient and type of message for logging
+func messageInfo(m Message) (recipientID string, messageType string) {
+	switch m := m.(type) {
+	case TextMessage:
+		return m.Recipient.ID, "text"
+	case *TextMessage:
+		return m.Recipient.ID, "text"
+	case GenericMessage:
+		return m.Recipient.ID, "generic"
+	case *GenericMessage:
+		return m.Recipient.ID, "generic"
+	case ButtonMessage:
+		return m.Recipient.ID, "button"
+	case *ButtonMessage:
+		return m.Recipient.ID, "button"
+	case OneTimeNotifRequestMessage:
+		return m.Recipient.ID, "one_time_notif_req"
+	case *OneTimeNotifRequestMessage:
+		return m.Recipient.ID, "one_time_notif_req"
+	case interface{ messageInfo() (string, string) }: // TemplateMessage, retryMessage
+		return m.messageInfo()
+	}
+	return "", "unknown"
+}
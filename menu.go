@@ -0,0 +1,105 @@
+package messenger
+
+import (
+	"context"
+	"fmt"
+)
+
+// maxMenuDepth is maximum number of nested submenu levels in persistent menu
+const maxMenuDepth = 2
+
+// MenuItemType is type of persistent menu item
+type MenuItemType string
+
+const (
+	// MenuItemTypePostback sends payload back to webhook when tapped
+	MenuItemTypePostback = MenuItemType("postback")
+
+	// MenuItemTypeWebURL opens URL when tapped
+	MenuItemTypeWebURL = MenuItemType("web_url")
+
+	// MenuItemTypeNested opens submenu with its own items
+	MenuItemTypeNested = MenuItemType("nested")
+)
+
+// LocaleMenu is locale of persistent menu, "default" or locale like en_US
+type LocaleMenu string
+
+// LocaleMenuDefault is menu shown when there is no menu for user's locale
+const LocaleMenuDefault = LocaleMenu("default")
+
+// PersistentMenu is persistent menu for one locale
+type PersistentMenu struct {
+	Locale                LocaleMenu `json:"locale"`
+	ComposerInputDisabled bool       `json:"composer_input_disabled"`
+	CallToActions         []MenuItem `json:"call_to_actions"`
+}
+
+// MenuItem of persistent menu, nested items have only title and their own call to actions
+type MenuItem struct {
+	Type             MenuItemType `json:"type"`
+	Title            string       `json:"title"`
+	URL              string       `json:"url,omitempty"`
+	Payload          string       `json:"payload,omitempty"`
+	CallToActions    []MenuItem   `json:"call_to_actions,omitempty"`
+	DisabledSurfaces []string     `json:"disabled_surfaces,omitempty"`
+}
+
+// NewPostbackMenuItem creates menu item that sends payload back to webhook when tapped
+func NewPostbackMenuItem(title, payload string) MenuItem {
+	return MenuItem{Type: MenuItemTypePostback, Title: title, Payload: payload}
+}
+
+// NewWebURLMenuItem creates menu item that opens URL when tapped
+func NewWebURLMenuItem(title, URL string) MenuItem {
+	return MenuItem{Type: MenuItemTypeWebURL, Title: title, URL: URL}
+}
+
+// NewNestedMenuItem creates submenu with items, menu can be nested up to two levels
+func NewNestedMenuItem(title string, items []MenuItem) MenuItem {
+	return MenuItem{Type: MenuItemTypeNested, Title: title, CallToActions: items}
+}
+
+// SetPersistentMenu sets persistent menu, one menu per locale
+func (msng *Messenger) SetPersistentMenu(ctx context.Context, menus []PersistentMenu) error {
+	for _, m := range menus {
+		if err := m.validate(); err != nil {
+			return err
+		}
+	}
+	return msng.setMessengerProfile(ctx, map[string]interface{}{"persistent_menu": menus})
+}
+
+// DeletePersistentMenu removes persistent menu from messenger profile
+func (msng *Messenger) DeletePersistentMenu(ctx context.Context) error {
+	return msng.DeleteMessengerProfileFields(ctx, []string{"persistent_menu"})
+}
+
+func (m PersistentMenu) validate() error {
+	if !ValidateLocale(string(m.Locale)) {
+		return fmt.Errorf("%w: %q", ErrInvalidLocale, m.Locale)
+	}
+	return validateMenuItems(m.CallToActions, 0)
+}
+
+func validateMenuItems(items []MenuItem, depth int) error {
+	for _, item := range items {
+		if item.Type != MenuItemTypeNested {
+			if len(item.CallToActions) > 0 {
+				return fmt.Errorf("%w: %q only nested item can have call to actions", ErrInvalidMenu, item.Title)
+			}
+			continue
+		}
+
+		if depth >= maxMenuDepth {
+			return fmt.Errorf("%w: %q nested more than %d levels", ErrInvalidMenu, item.Title, maxMenuDepth)
+		}
+		if item.URL != "" || item.Payload != "" {
+			return fmt.Errorf("%w: %q nested item can't have URL or payload", ErrInvalidMenu, item.Title)
+		}
+		if err := validateMenuItems(item.CallToActions, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package messenger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestPersistentMenuValidation(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+	leaf := messenger.NewPostbackMenuItem("Help", "HELP")
+
+	menu := messenger.PersistentMenu{
+		Locale: messenger.LocaleMenuDefault,
+		CallToActions: []messenger.MenuItem{
+			messenger.NewNestedMenuItem("More", []messenger.MenuItem{
+				messenger.NewNestedMenuItem("Even more", []messenger.MenuItem{leaf}),
+			}),
+		},
+	}
+	if err := msng.SetPersistentMenu(context.Background(), []messenger.PersistentMenu{menu}); err != nil {
+		t.Error("Two levels of nesting should be valid", err)
+	}
+
+	menu.CallToActions = []messenger.MenuItem{
+		messenger.NewNestedMenuItem("1", []messenger.MenuItem{
+			messenger.NewNestedMenuItem("2", []messenger.MenuItem{
+				messenger.NewNestedMenuItem("3", []messenger.MenuItem{leaf}),
+			}),
+		}),
+	}
+	if err := msng.SetPersistentMenu(context.Background(), []messenger.PersistentMenu{menu}); !errors.Is(err, messenger.ErrInvalidMenu) {
+		t.Error("Expected ErrInvalidMenu for three levels, returned", err)
+	}
+
+	nested := messenger.NewNestedMenuItem("More", []messenger.MenuItem{leaf})
+	nested.Payload = "MORE"
+	menu.CallToActions = []messenger.MenuItem{nested}
+	if err := msng.SetPersistentMenu(context.Background(), []messenger.PersistentMenu{menu}); !errors.Is(err, messenger.ErrInvalidMenu) {
+		t.Error("Expected ErrInvalidMenu for nested item with payload, returned", err)
+	}
+
+	menu = messenger.PersistentMenu{Locale: "en-US", CallToActions: []messenger.MenuItem{leaf}}
+	if err := msng.SetPersistentMenu(context.Background(), []messenger.PersistentMenu{menu}); !errors.Is(err, messenger.ErrInvalidLocale) {
+		t.Error("Expected ErrInvalidLocale, returned", err)
+	}
+}
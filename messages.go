@@ -1,5 +1,19 @@
 package messenger
 
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Generic template element limits
+const (
+	maxElementTitleLength    = 80
+	maxElementSubtitleLength = 80
+	maxElements              = 10
+	maxElementButtons        = 3
+)
+
 // ButtonType for buttons, it can be ButtonTypeWebURL or ButtonTypePostback
 type ButtonType string
 
@@ -12,6 +26,21 @@ type TemplateType string
 // NotificationType for sent messages
 type NotificationType string
 
+// MessagingType of sent message, tells Facebook why message is sent
+type MessagingType string
+
+// MessageTag allows sending messages outside of 24 hour window, requires MessagingTypeMessageTag
+type MessageTag string
+
+// SubscriptionCategory of non promotional subscription messages
+type SubscriptionCategory string
+
+// WebviewHeightRatio is height of webview opened by URL button or default action
+type WebviewHeightRatio string
+
+// ImageAspectRatio of images in generic template, it can be AspectRatioHorizontal or AspectRatioSquare
+type ImageAspectRatio string
+
 // Message interface that represents all type of messages that we can send to Facebook Messenger
 type Message interface {
 	foo()
@@ -19,6 +48,9 @@ type Message interface {
 
 func (m TextMessage) foo()    {} // Message interface
 func (m GenericMessage) foo() {} // Message interface
+func (m ButtonMessage) foo()  {} // Message interface
+
+func (m OneTimeNotifRequestMessage) foo() {} // Message interface
 
 const (
 	// ButtonTypeWebURL is type for web links
@@ -33,6 +65,12 @@ const (
 	// TemplateTypeGeneric for generic message templates
 	TemplateTypeGeneric = TemplateType("generic")
 
+	// TemplateTypeButton for button message templates
+	TemplateTypeButton = TemplateType("button")
+
+	// TemplateTypeOneTimeNotifReq for one time notification request templates
+	TemplateTypeOneTimeNotifReq = TemplateType("one_time_notif_req")
+
 	// NotificationTypeRegular for regular notification type
 	NotificationTypeRegular = NotificationType("REGULAR")
 
@@ -41,6 +79,54 @@ const (
 
 	// NotificationTypeNoPush for no push
 	NotificationTypeNoPush = NotificationType("NO_PUSH")
+
+	// MessagingTypeResponse for messages sent in response to received message
+	MessagingTypeResponse = MessagingType("RESPONSE")
+
+	// MessagingTypeUpdate for proactive messages sent within 24 hour window
+	MessagingTypeUpdate = MessagingType("UPDATE")
+
+	// MessagingTypeMessageTag for tagged messages sent outside of 24 hour window
+	MessagingTypeMessageTag = MessagingType("MESSAGE_TAG")
+
+	// MessagingTypeSubscription for non promotional subscription messages (legacy message_subscription)
+	MessagingTypeSubscription = MessagingType("NON_PROMOTIONAL_SUBSCRIPTION")
+
+	// MessageTagConfirmedEventUpdate for reminders and updates of event user has registered for
+	MessageTagConfirmedEventUpdate = MessageTag("CONFIRMED_EVENT_UPDATE")
+
+	// MessageTagPostPurchaseUpdate for updates about user's purchase
+	MessageTagPostPurchaseUpdate = MessageTag("POST_PURCHASE_UPDATE")
+
+	// MessageTagAccountUpdate for non recurring changes of user's account
+	MessageTagAccountUpdate = MessageTag("ACCOUNT_UPDATE")
+
+	// MessageTagHumanAgent for human agent replies within 7 days of user's message
+	MessageTagHumanAgent = MessageTag("HUMAN_AGENT")
+
+	// SubscriptionCategoryNews for news updates
+	SubscriptionCategoryNews = SubscriptionCategory("NEWS")
+
+	// SubscriptionCategoryProductivity for productivity reminders, like calendar or to do
+	SubscriptionCategoryProductivity = SubscriptionCategory("PRODUCTIVITY")
+
+	// SubscriptionCategoryPersonalTracker for personal trackers, like fitness or finance
+	SubscriptionCategoryPersonalTracker = SubscriptionCategory("PERSONAL_TRACKER")
+
+	// AspectRatioHorizontal for 1.91:1 images, default
+	AspectRatioHorizontal = ImageAspectRatio("horizontal")
+
+	// AspectRatioSquare for 1:1 images
+	AspectRatioSquare = ImageAspectRatio("square")
+
+	// WebviewHeightRatioCompact for webview covering half of the screen
+	WebviewHeightRatioCompact = WebviewHeightRatio("compact")
+
+	// WebviewHeightRatioTall for webview covering 75% of the screen
+	WebviewHeightRatioTall = WebviewHeightRatio("tall")
+
+	// WebviewHeightRatioFull for full screen webview
+	WebviewHeightRatioFull = WebviewHeightRatio("full")
 )
 
 // TextMessage struct used for sending text messages to messenger
@@ -48,6 +134,8 @@ type TextMessage struct {
 	Message          textMessageContent `json:"message"`
 	Recipient        recipient          `json:"recipient"`
 	NotificationType NotificationType   `json:"notification_type,omitempty"`
+	MessagingType    MessagingType      `json:"messaging_type,omitempty"`
+	Tag              MessageTag         `json:"tag,omitempty"`
 }
 
 // GenericMessage struct used for sending structural messages to messenger (messages with images, links, and buttons)
@@ -55,18 +143,42 @@ type GenericMessage struct {
 	Message          genericMessageContent `json:"message"`
 	Recipient        recipient             `json:"recipient"`
 	NotificationType NotificationType      `json:"notification_type,omitempty"`
+	MessagingType    MessagingType         `json:"messaging_type,omitempty"`
+	Tag              MessageTag            `json:"tag,omitempty"`
+}
+
+// ButtonMessage struct used for sending text with up to 3 buttons to messenger
+type ButtonMessage struct {
+	Message          genericMessageContent `json:"message"`
+	Recipient        recipient             `json:"recipient"`
+	NotificationType NotificationType      `json:"notification_type,omitempty"`
+	MessagingType    MessagingType         `json:"messaging_type,omitempty"`
+	Tag              MessageTag            `json:"tag,omitempty"`
 }
 
 type recipient struct {
-	ID int64 `json:"id,string"`
+	ID string `json:"id"`
+}
+
+// newRecipient creates recipient with page scoped user ID
+func newRecipient(id string) recipient {
+	return recipient{ID: id}
 }
 
 type textMessageContent struct {
-	Text string `json:"text,omitempty"`
+	Text     string            `json:"text,omitempty"`
+	Metadata string            `json:"metadata,omitempty"` // up to 1000 characters, sent back in message echo
+	ReplyTo  *MessageReference `json:"reply_to,omitempty"`
+}
+
+// MessageReference references message by its message ID
+type MessageReference struct {
+	Mid string `json:"mid"`
 }
 
 type genericMessageContent struct {
 	Attachment *attachment `json:"attachment,omitempty"`
+	Metadata   string      `json:"metadata,omitempty"` // up to 1000 characters, sent back in message echo
 }
 
 type attachment struct {
@@ -75,17 +187,34 @@ type attachment struct {
 }
 
 type payload struct {
-	TemplateType string    `json:"template_type,omitempty"`
-	Elements     []Element `json:"elements,omitempty"`
+	TemplateType     string           `json:"template_type,omitempty"`
+	Text             string           `json:"text,omitempty"` // button template only
+	ImageAspectRatio ImageAspectRatio `json:"image_aspect_ratio,omitempty"`
+	Sharable         bool             `json:"sharable,omitempty"`
+	Elements         []Element        `json:"elements,omitempty"`
+	Buttons          []Button         `json:"buttons,omitempty"` // button template only
+	Title            string           `json:"title,omitempty"`   // one time notification request only
+	Payload          string           `json:"payload,omitempty"` // one time notification request only
 }
 
 // Element in Generic Message template attachment
 type Element struct {
-	Title    string   `json:"title"`
-	Subtitle string   `json:"subtitle,omitempty"`
-	ItemURL  string   `json:"item_url,omitempty"`
-	ImageURL string   `json:"image_url,omitempty"`
-	Buttons  []Button `json:"buttons,omitempty"`
+	Title         string         `json:"title"`
+	Subtitle      string         `json:"subtitle,omitempty"`
+	ItemURL       string         `json:"item_url,omitempty"`
+	ImageURL      string         `json:"image_url,omitempty"`
+	DefaultAction *DefaultAction `json:"default_action,omitempty"`
+	Buttons       []Button       `json:"buttons,omitempty"`
+}
+
+// DefaultAction is executed when user taps the element anywhere except on buttons
+type DefaultAction struct {
+	Type                string             `json:"type"`
+	URL                 string             `json:"url"`
+	WebviewHeightRatio  WebviewHeightRatio `json:"webview_height_ratio,omitempty"`
+	MessengerExtensions bool               `json:"messenger_extensions,omitempty"`
+	FallbackURL         string             `json:"fallback_url,omitempty"`         // required if MessengerExtensions is true
+	WebviewShareButton  string             `json:"webview_share_button,omitempty"` // "hide" to hide share button in webview
 }
 
 // Button on Generic Message template element
@@ -94,6 +223,11 @@ type Button struct {
 	URL     string     `json:"url,omitempty"`
 	Title   string     `json:"title"`
 	Payload string     `json:"payload,omitempty"`
+
+	// web url buttons only
+	WebviewHeightRatio  WebviewHeightRatio `json:"webview_height_ratio,omitempty"`
+	MessengerExtensions bool               `json:"messenger_extensions,omitempty"`
+	FallbackURL         string             `json:"fallback_url,omitempty"`
 }
 
 // NewTextMessage creates new text message for userID
@@ -101,16 +235,39 @@ type Button struct {
 // probably use shorthand version SentTextMessage which sends message immediatly
 func (msng Messenger) NewTextMessage(userID int64, text string) TextMessage {
 	return TextMessage{
-		Recipient: recipient{ID: userID},
+		Recipient: newRecipient(strconv.FormatInt(userID, 10)),
 		Message:   textMessageContent{Text: text},
 	}
 }
 
+// SetReplyToMessageID makes text message a reply to message with message ID mid
+func (m *TextMessage) SetReplyToMessageID(mid string) {
+	m.Message.ReplyTo = &MessageReference{Mid: mid}
+}
+
+// TemplateOption configures template message, e.g. one created with NewGenericMessage
+type TemplateOption func(p *payload)
+
+// WithAspectRatio sets aspect ratio of all images in Generic Template message.
+// Invalid aspect ratio is reported by SendMessage
+func WithAspectRatio(r ImageAspectRatio) TemplateOption {
+	return func(p *payload) {
+		p.ImageAspectRatio = r
+	}
+}
+
+// WithSharable sets if template message can be shared with share button, messages are not sharable by default
+func WithSharable(sharable bool) TemplateOption {
+	return func(p *payload) {
+		p.Sharable = sharable
+	}
+}
+
 // NewGenericMessage creates new Generic Template message for userID
 // Generic template messages are used for structured messages with images, links, buttons and postbacks
-func (msng Messenger) NewGenericMessage(userID int64) GenericMessage {
-	return GenericMessage{
-		Recipient: recipient{ID: userID},
+func (msng Messenger) NewGenericMessage(userID int64, opts ...TemplateOption) GenericMessage {
+	m := GenericMessage{
+		Recipient: newRecipient(strconv.FormatInt(userID, 10)),
 		Message: genericMessageContent{
 			Attachment: &attachment{
 				Type:    "template",
@@ -118,19 +275,95 @@ func (msng Messenger) NewGenericMessage(userID int64) GenericMessage {
 			},
 		},
 	}
+	for _, opt := range opts {
+		opt(&m.Message.Attachment.Payload)
+	}
+	return m
+}
+
+// NewButtonMessage creates new Button Template message for userID with text and up to 3 buttons
+func (msng Messenger) NewButtonMessage(userID int64, text string, buttons []Button, opts ...TemplateOption) ButtonMessage {
+	m := ButtonMessage{
+		Recipient: newRecipient(strconv.FormatInt(userID, 10)),
+		Message: genericMessageContent{
+			Attachment: &attachment{
+				Type: "template",
+				Payload: payload{
+					TemplateType: string(TemplateTypeButton),
+					Text:         text,
+					Buttons:      buttons,
+				},
+			},
+		},
+	}
+	for _, opt := range opts {
+		opt(&m.Message.Attachment.Payload)
+	}
+	return m
+}
+
+// SetAspectRatio sets aspect ratio of all images in Generic Template message
+func (m *GenericMessage) SetAspectRatio(r ImageAspectRatio) error {
+	if err := validateAspectRatio(r); err != nil {
+		return err
+	}
+	m.Message.Attachment.Payload.ImageAspectRatio = r
+	return nil
+}
+
+func validateAspectRatio(r ImageAspectRatio) error {
+	switch r {
+	case "", AspectRatioHorizontal, AspectRatioSquare:
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidAspectRatio, r)
 }
 
 // AddNewElement adds element to Generic template message with defined title, subtitle, link url and image url
 // Title param is mandatory. If not used set "" for other params and nil for buttons param
 // Generic messages can have up to 10 elements which are scolled horizontaly in Facebook messenger
-func (m *GenericMessage) AddNewElement(title, subtitle, itemURL, imageURL string, buttons []Button) {
-	m.AddElement(newElement(title, subtitle, itemURL, imageURL, buttons))
+func (m *GenericMessage) AddNewElement(title, subtitle, itemURL, imageURL string, buttons []Button) error {
+	return m.AddElement(newElement(title, subtitle, itemURL, imageURL, buttons))
 }
 
 // AddElement adds element e to Generic Message
 // Generic messages can have up to 10 elements which are scolled horizontaly in Facebook messenger
-func (m *GenericMessage) AddElement(e Element) {
+// Title and subtitle can have up to 80 characters, use TruncateElement for dynamic content
+// Element's DefaultAction, if set, opens when user taps the card outside of buttons, see WithDefaultAction
+func (m *GenericMessage) AddElement(e Element) error {
+	if err := e.validate(); err != nil {
+		return err
+	}
 	m.Message.Attachment.Payload.Elements = append(m.Message.Attachment.Payload.Elements, e)
+	return nil
+}
+
+// validate checks element title and subtitle length and element default action
+func (e Element) validate() error {
+	if n := utf8.RuneCountInString(e.Title); n > maxElementTitleLength {
+		return fmt.Errorf("%w: %d characters", ErrTitleTooLong, n)
+	}
+	if n := utf8.RuneCountInString(e.Subtitle); n > maxElementSubtitleLength {
+		return fmt.Errorf("%w: %d characters", ErrSubtitleTooLong, n)
+	}
+	if e.DefaultAction != nil {
+		return e.DefaultAction.Validate()
+	}
+	return nil
+}
+
+// TruncateElement returns element with title and subtitle truncated to allowed length, truncated text ends with "…"
+func TruncateElement(el Element) Element {
+	el.Title = truncate(el.Title, maxElementTitleLength)
+	el.Subtitle = truncate(el.Subtitle, maxElementSubtitleLength)
+	return el
+}
+
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max-1]) + "…"
 }
 
 // NewElement creates new element with defined title, subtitle, link url and image url
@@ -150,6 +383,31 @@ func newElement(title, subtitle, itemURL, imageURL string, buttons []Button) Ele
 	}
 }
 
+// NewURLDefaultAction creates default action that opens url
+func NewURLDefaultAction(url string) DefaultAction {
+	return DefaultAction{
+		Type: string(ButtonTypeWebURL),
+		URL:  url,
+	}
+}
+
+// Validate checks if default action is valid, FallbackURL is required when MessengerExtensions is true
+func (a DefaultAction) Validate() error {
+	if a.MessengerExtensions && a.FallbackURL == "" {
+		return ErrFallbackURLRequired
+	}
+	return nil
+}
+
+// WithDefaultAction sets default action of the element, error is returned if action is not valid
+func (e *Element) WithDefaultAction(a DefaultAction) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	e.DefaultAction = &a
+	return nil
+}
+
 // NewWebURLButton creates new web url button
 func (msng Messenger) NewWebURLButton(title, URL string) Button {
 	return Button{
@@ -0,0 +1,76 @@
+package messenger_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestMessagesOmitOptionalFields(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+
+	// only required fields are set, optional fields must not be encoded
+	text := msng.NewTextMessage(123, "Text")
+	generic := msng.NewGenericMessage(123)
+	generic.AddElement(msng.NewElement("Title", "", "", "", []messenger.Button{msng.NewWebURLButton("Title", "https://example.com")}))
+	button := msng.NewButtonMessage(123, "Text", []messenger.Button{msng.NewPostbackButton("Title", "PAYLOAD")})
+	otn := messenger.NewOneTimeNotifRequest("123", "Title", "PAYLOAD")
+
+	messages := map[string]messenger.Message{
+		"TextMessage":                &text,
+		"GenericMessage":             &generic,
+		"ButtonMessage":              &button,
+		"OneTimeNotifRequestMessage": &otn,
+	}
+	for name, m := range messages {
+		b, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		var v interface{}
+		json.Unmarshal(b, &v)
+		if path := emptyField(v, ""); path != "" {
+			t.Errorf("%s encoded empty field %s: %s", name, path, b)
+		}
+	}
+}
+
+// emptyField returns path of first field in decoded JSON v that has zero value
+func emptyField(v interface{}, path string) string {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return path
+		}
+		for k, f := range v {
+			if p := emptyField(f, path+"."+k); p != "" {
+				return p
+			}
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return path
+		}
+		for _, f := range v {
+			if p := emptyField(f, path+"[]"); p != "" {
+				return p
+			}
+		}
+	case string:
+		if v == "" {
+			return path
+		}
+	case bool:
+		if !v {
+			return path
+		}
+	case float64:
+		if v == 0 {
+			return path
+		}
+	case nil:
+		return path
+	}
+	return ""
+}
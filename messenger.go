@@ -2,14 +2,21 @@ package messenger
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strings"
+	"time"
 )
 
-const apiURL = "https://graph.facebook.com/v2.6/"
+const apiURL = "https://graph.facebook.com/" + DefaultAPIVersion + "/"
 
 // TestURL to mock FB server, used for testing
+//
+// Deprecated: TestURL is shared by all messengers so tests using it can't run in parallel,
+// use WithTestURL instead. TestURL will be removed in the next release.
 var TestURL = ""
 
 // Messenger struct
@@ -17,11 +24,83 @@ type Messenger struct {
 	AccessToken string
 	VerifyToken string
 	PageID      string
+	AppID       string // used for app level API calls like ConfigureWebhook, omit if you don't use them
+	AppSecret   string // used for validating signed requests, omit if you don't use them
 
 	HttpClient *http.Client
 
-	apiURL  string
-	pageURL string
+	// APIVersion is Graph API version used for API calls, e.g. "v6.0". DefaultAPIVersion if empty
+	APIVersion string
+
+	// Logger logs sent messages and received events, omit (nil) to log with slog.Default()
+	Logger Logger
+
+	// EventLog records all received webhook events, omit (nil) if you don't need audit trail
+	EventLog EventLog
+
+	// Metrics collects send and webhook metrics, omit (nil) if you don't collect metrics
+	Metrics MetricsRecorder
+
+	// SequenceTracker tracks message sequence numbers per user, omit (nil) if you don't track message order
+	SequenceTracker *SequenceTracker
+
+	// DomainValidator checks domains of URLs opened with messenger extensions, omit (nil) to skip the check
+	DomainValidator DomainValidator
+
+	// ConversationManager stores per user conversation state, omit (nil) if your bot is stateless
+	ConversationManager ConversationManager
+
+	// ConversationLock makes event handlers of the same user run one at a time,
+	// omit (nil) if your handlers don't share per user state
+	ConversationLock *ConversationLock
+
+	// TypingManager prevents flickering of typing indicator shown by concurrent replies, see StartTyping.
+	// Omit (nil) if you don't reply to the same user concurrently
+	TypingManager *TypingManager
+
+	// DefaultPersonaID is persona all messages are sent as, omit (empty) to send messages as page
+	DefaultPersonaID string
+
+	// BlockList stores users who opted out, messages are not sent to them. Omit (nil) if you don't track opt outs
+	BlockList BlockList
+
+	// OptOutKeywords opt user out when received as message, DefaultOptOutKeywords if nil
+	OptOutKeywords []string
+
+	// SendTimeout cancels sending of every message not sent within timeout, see WithCallTimeout
+	// for overriding it per message. Omit (0) for no timeout
+	SendTimeout time.Duration
+
+	// OTNStore stores one time notification tokens received on webhook, used by SendStoredOTNMessage.
+	// Omit (nil) if you don't send one time notifications
+	OTNStore OTNStore
+
+	// RetryQueue stores messages that failed to send because of network errors or rate limiting,
+	// they are resent by StartRetryWorker. Omit (nil) if you don't retry failed messages
+	RetryQueue RetryQueue
+
+	// Localizer translates messages sent with SendLocalizedText, omit (nil) if you don't localize messages
+	Localizer *Localizer
+
+	// RequestIDHeader is header with request ID copied from webhook request to messages sent by event handlers,
+	// used for tracing. Omit (empty) if you don't trace requests
+	RequestIDHeader string
+	requestID       string // request ID of webhook request, set for event handlers
+
+	middleware []EventMiddleware // added with Use
+	eventCtx   context.Context   // context of event, set for event handlers
+
+	// MaxConcurrentHandlers limits number of event handlers running at the same time, ServeHTTP waits
+	// for running handlers to finish when limit is reached. Omit (0) for no limit
+	MaxConcurrentHandlers int
+	handlerSem            chan struct{} // semaphore for MaxConcurrentHandlers
+
+	// TokenProvider provides access token for API calls instead of AccessToken, omit (nil) to use AccessToken
+	TokenProvider AccessTokenProvider
+
+	pageInfo       *PageInfo     // cached by GetPageInfo
+	botInfo        *botInfoCache // cache of GetBotInfo, shared with copies
+	apiURLOverride string        // mock FB server URL, set by WithTestURL
 
 	// MessageReceived event fires when message from Facebook received
 	MessageReceived func(msng *Messenger, userID int64, m FacebookMessage)
@@ -39,79 +118,395 @@ type Messenger struct {
 
 	//
 	ReadReceived func(msng *Messenger, userID int64, p FacebookRead)
+
+	// PassThreadControlReceived event fires when other app passes thread control to your app
+	// Omit (nil) if you don't use handover protocol
+	PassThreadControlReceived func(msng *Messenger, userID int64, e HandoverEvent)
+
+	// RequestThreadControlReceived event fires when secondary receiver app asks your app to pass thread control
+	// Omit (nil) if you don't use handover protocol
+	RequestThreadControlReceived func(msng *Messenger, userID int64, e HandoverEvent)
+
+	// OnOutOfOrderEvent fires when message sequence number doesn't follow the previous one from the same user
+	// Requires SequenceTracker, omit (nil) if you don't track message order
+	OnOutOfOrderEvent func(msng *Messenger, userID int64, expected, got int64)
+
+	// OptOutReceived event fires when user sends one of OptOutKeywords and is added to BlockList
+	OptOutReceived func(msng *Messenger, userID int64)
+
+	// OnDecodeError is called when webhook request body can't be decoded or isn't valid page event,
+	// it should write the response. Omit (nil) to respond with 400 Bad Request
+	OnDecodeError func(w http.ResponseWriter, r *http.Request, err error)
+
+	// OnSubscriptionError is called when WatchSubscription can't check or restore webhook subscription.
+	// Omit (nil) to only log the error
+	OnSubscriptionError func(err error)
+
+	// FeedReceived event fires when page feed change received from Facebook server
+	// Omit (nil) if your page is not subscribed to feed webhook field
+	FeedReceived func(msng *Messenger, e FacebookFeedEntry)
 }
 
-// New creates new messenger instance
-func New(accessToken, pageID string) Messenger {
-	return Messenger{
+// Option configures Messenger created with New
+type Option func(msng *Messenger)
+
+// New creates new messenger instance. It doesn't call Graph API, use Verify to check configuration on startup
+func New(accessToken, pageID string, opts ...Option) Messenger {
+	msng := Messenger{
 		AccessToken: accessToken,
 		PageID:      pageID,
+		HttpClient:  &http.Client{},
+		botInfo:     &botInfoCache{},
+	}
+	for _, opt := range opts {
+		opt(&msng)
 	}
+	return msng
 }
 
+// Clone returns copy of messenger with overrides applied. Event handlers, middleware, HttpClient, Logger,
+// Metrics, ConversationLock, TypingManager and other settings are kept and shared with the original.
 //
+// If overrides change access token or page ID, page scoped components TokenProvider, RetryQueue, BlockList,
+// SequenceTracker, OTNStore and ConversationManager are not kept, since their items and user IDs belong to
+// the original page. Set them with overrides to use them for the other page, and start StartRetryWorker
+// for the clone's RetryQueue. Page info cached by GetPageInfo and GetBotInfo is not kept either
+func (msng *Messenger) Clone(overrides ...Option) Messenger {
+	cacheMu.Lock()
+	clone := *msng
+	cacheMu.Unlock()
+	clone.middleware = append(msng.middleware[:0:0], msng.middleware...)
+	clone.OptOutKeywords = append(msng.OptOutKeywords[:0:0], msng.OptOutKeywords...)
+
+	// page scoped components are set again below if page doesn't change, so overrides can tell
+	// which of them they set
+	clone.clearPageScoped()
+	for _, opt := range overrides {
+		opt(&clone)
+	}
+	if clone.AccessToken == msng.AccessToken && clone.PageID == msng.PageID {
+		clone.keepPageScoped(msng)
+	}
+	return clone
+}
+
+// clearPageScoped removes components that hold state of messenger's page
+func (msng *Messenger) clearPageScoped() {
+	msng.TokenProvider = nil
+	msng.RetryQueue = nil
+	msng.BlockList = nil
+	msng.SequenceTracker = nil
+	msng.OTNStore = nil
+	msng.ConversationManager = nil
+	msng.pageInfo = nil
+	msng.botInfo = &botInfoCache{}
+}
+
+// keepPageScoped sets page scoped components of orig that are not set
+func (msng *Messenger) keepPageScoped(orig *Messenger) {
+	if msng.TokenProvider == nil {
+		msng.TokenProvider = orig.TokenProvider
+	}
+	if msng.RetryQueue == nil {
+		msng.RetryQueue = orig.RetryQueue
+	}
+	if msng.BlockList == nil {
+		msng.BlockList = orig.BlockList
+	}
+	if msng.SequenceTracker == nil {
+		msng.SequenceTracker = orig.SequenceTracker
+	}
+	if msng.OTNStore == nil {
+		msng.OTNStore = orig.OTNStore
+	}
+	if msng.ConversationManager == nil {
+		msng.ConversationManager = orig.ConversationManager
+	}
+	msng.botInfo = orig.botInfoCache()
+	cacheMu.Lock()
+	msng.pageInfo = orig.pageInfo
+	cacheMu.Unlock()
+}
+
+// WithAccessToken sets page access token, used with Clone to create messenger for other page
+func WithAccessToken(token string) Option {
+	return func(msng *Messenger) {
+		msng.AccessToken = token
+	}
+}
+
+// WithPageID sets page ID, used with Clone to create messenger for other page
+func WithPageID(pageID string) Option {
+	return func(msng *Messenger) {
+		msng.PageID = pageID
+	}
+}
+
+// WithTestURL points messenger to mock FB server at url instead of Graph API, used for testing
+func WithTestURL(url string) Option {
+	return func(msng *Messenger) {
+		if !strings.HasSuffix(url, "/") {
+			url += "/"
+		}
+		msng.apiURLOverride = url
+	}
+}
+
+// defaultHTTPClient is used by messengers without HttpClient that are not created with New
+var defaultHTTPClient = &http.Client{}
+
+// GetClient returns HTTP client used for calling Facebook API, default client if HttpClient is not set
 func (msng *Messenger) GetClient() *http.Client {
 	if msng.HttpClient == nil {
-		msng.HttpClient = &http.Client{}
+		return defaultHTTPClient
 	}
-
 	return msng.HttpClient
 }
 
-// SendMessage sends chat message
-func (msng *Messenger) SendMessage(m Message) (FacebookResponse, error) {
-	if msng.apiURL == "" {
-		if TestURL != "" {
-			msng.apiURL = TestURL + "me/messages?access_token=" + msng.AccessToken // testing, mock FB URL
-		} else {
-			msng.apiURL = apiURL + "me/messages?access_token=" + msng.AccessToken
-		}
+// SendMessage sends chat message, opts are applied to the message before sending
+func (msng *Messenger) SendMessage(m Message, opts ...SendOption) (FacebookResponse, error) {
+	return msng.sendMessage(context.Background(), m, opts)
+}
+
+func (msng *Messenger) sendMessage(ctx context.Context, m Message, opts []SendOption) (FacebookResponse, error) {
+	resp, err := msng.send(ctx, m, opts)
+	if err != nil && msng.RetryQueue != nil && isTransient(err) && sendDeadline(opts) == 0 {
+		msng.enqueueRetry(m, opts)
+	}
+	return resp, err
+}
+
+// withDefaultPersona adds DefaultPersonaID to send options of m, opts can override it
+func (msng *Messenger) withDefaultPersona(m Message, opts []SendOption) []SendOption {
+	if _, ok := m.(retryMessage); ok || msng.DefaultPersonaID == "" {
+		return opts // resent message already has persona it was sent with
 	}
+	return append([]SendOption{WithPersona(msng.DefaultPersonaID)}, opts...)
+}
 
-	s, _ := json.Marshal(m)
-	log.Println("MESSAGE:", string(s))
-	req, err := http.NewRequest("POST", msng.apiURL, bytes.NewBuffer(s))
+// send sends message m without retrying
+func (msng *Messenger) send(ctx context.Context, m Message, opts []SendOption) (FacebookResponse, error) {
+	if err := ValidateMessageForVersion(msng.apiVersion(), m); err != nil {
+		return FacebookResponse{}, err
+	}
+	if d := msng.sendTimeout(opts); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+	s, err := encodeMessage(m, msng.withDefaultPersona(m, opts))
+	if err != nil {
+		return FacebookResponse{}, err
+	}
+	if err := validatePolicy(s); err != nil {
+		return FacebookResponse{}, err
+	}
+	if err := checkLimits(s); err != nil {
+		return FacebookResponse{}, err
+	}
+	if err := msng.checkDomains(s); err != nil {
+		return FacebookResponse{}, err
+	}
+	if err := msng.checkBlocked(s); err != nil {
+		return FacebookResponse{}, err
+	}
+
+	token, err := msng.accessToken(ctx)
+	if err != nil {
+		return FacebookResponse{}, err
+	}
+
+	msng.logger().Debug("sending message", "body", string(s))
+	req, err := http.NewRequestWithContext(ctx, "POST", msng.graphURL("me/messages", nil, token), bytes.NewBuffer(s))
 	req.Header.Set("Content-Type", "application/json")
+	if msng.RequestIDHeader != "" && msng.requestID != "" {
+		req.Header.Set(msng.RequestIDHeader, msng.requestID)
+	}
 
+	start := time.Now()
 	resp, err := msng.GetClient().Do(req)
 	if err != nil {
+		msng.metrics().ObserveSend(time.Since(start), err)
+		msng.logSend(m, FacebookResponse{}, err)
 		return FacebookResponse{}, err
 	}
 
-	return decodeResponse(resp)
+	fbResp, err := decodeResponse(resp)
+	msng.metrics().ObserveSend(time.Since(start), err)
+	msng.logSend(m, fbResp, err)
+	return fbResp, err
 }
 
 // SendTextMessage sends text messate to receiverID
 // it is shorthand instead of crating new text message and then sending it
+//
+// Deprecated: PSIDs are opaque strings, use SendTextMessageStr.
 func (msng Messenger) SendTextMessage(receiverID int64, text string) (FacebookResponse, error) {
 	m := msng.NewTextMessage(receiverID, text)
 	return msng.SendMessage(&m)
 }
 
-// ServeHTTP is HTTP handler for Messenger so it could be directly used as http.Handler
+// SendTextMessageStr sends text message to recipientID
+func (msng *Messenger) SendTextMessageStr(ctx context.Context, recipientID string, text string) (FacebookResponse, error) {
+	m := TextMessage{
+		Recipient: newRecipient(recipientID),
+		Message:   textMessageContent{Text: text},
+	}
+	return msng.sendMessage(ctx, &m, nil)
+}
+
+// SendTextMessageReply sends text message to recipientID as reply to message with message ID replyToMID
+func (msng *Messenger) SendTextMessageReply(ctx context.Context, recipientID, replyToMID, text string) (FacebookResponse, error) {
+	m := TextMessage{
+		Recipient: newRecipient(recipientID),
+		Message:   textMessageContent{Text: text},
+	}
+	m.SetReplyToMessageID(replyToMID)
+	return msng.sendMessage(ctx, &m, nil)
+}
+
+// SendWithTag sends message m to recipientID as tagged message with tag, outside of 24 hour window
+func (msng *Messenger) SendWithTag(ctx context.Context, recipientID string, m Message, tag MessageTag) (FacebookResponse, error) {
+	return msng.sendMessage(ctx, m, []SendOption{withRecipient(recipientID), WithTag(tag)})
+}
+
+// SendAsHumanAgent sends text message written by human agent to recipientID, up to 7 days after user's message
+func (msng *Messenger) SendAsHumanAgent(ctx context.Context, recipientID, text string) (FacebookResponse, error) {
+	m := TextMessage{
+		Recipient: newRecipient(recipientID),
+		Message:   textMessageContent{Text: text},
+	}
+	return msng.sendMessage(ctx, &m, []SendOption{WithTag(MessageTagHumanAgent)})
+}
+
+// ServeHTTP is HTTP handler for Messenger so it could be directly used as http.Handler.
+// POST requests are decoded and dispatched to event handlers without verification logic, other requests
+// are webhook verification, see WebhookVerifyHandler for routing them separately
 func (msng *Messenger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	fbRq, _ := DecodeRequest(r) // get FacebookRequest object
-	msng.VerifyWebhook(w, r)
+	start := time.Now()
+	defer func() { msng.metrics().ObserveWebhookProcessing(time.Since(start)) }()
+
+	var body []byte
+	if msng.EventLog != nil && r.Body != nil {
+		body, _ = ioutil.ReadAll(r.Body) // keep raw events for event log
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.Method != http.MethodPost {
+		msng.VerifyWebhook(w, r)
+		return
+	}
 
-	for _, entry := range fbRq.Entry {
-		for _, msg := range entry.Messaging {
+	fbRq, err := DecodeRequest(r) // get FacebookRequest object
+	if err != nil {
+		msng.decodeError(w, r, fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+		return
+	}
+
+	if err := fbRq.Validate(); err != nil {
+		msng.decodeError(w, r, err)
+		return
+	}
+
+	var raw [][]json.RawMessage
+	if msng.EventLog != nil {
+		raw = rawMessagingEvents(body)
+	}
+
+	msng.initHandlerSemaphore() // before copy, so handlers share it
+	msng.botInfoCache()         // same for bot info cache
+	hm := msng.withRequestID(r) // handlers get messenger that sends request ID with every message
+	for i, entry := range fbRq.Entry {
+		if entry.HasChanges() && !entry.HasMessaging() { // page feed entry
+			msng.logEvent("feed", 0)
+			msng.metrics().ObserveWebhookEvent("feed")
+			if msng.FeedReceived != nil {
+				go hm.FeedReceived(hm, FacebookFeedEntry{
+					ID:      entry.ID,
+					Time:    entry.Time,
+					Changes: entry.Changes,
+				})
+			}
+			continue
+		}
+
+		for j, msg := range entry.Messaging {
 			userID := msg.Sender.ID
-			switch {
-			case msg.Message != nil && msng.MessageReceived != nil:
-				go msng.MessageReceived(msng, userID, *msg.Message)
+			eventType := msg.EventType()
+			var handler func(hm *Messenger) // registered event handler, run by middleware chain
+			switch eventType {
+			case EventTypeMessage, EventTypeEcho, EventTypeUnsend:
+				if eventType == EventTypeMessage {
+					msng.trackSequence(userID, int64(msg.Message.Seq))
+					if msng.isOptOut(msg.Message.Text) {
+						go hm.optOut(userID)
+					}
+				}
+				if msng.MessageReceived != nil {
+					m := *msg.Message
+					handler = func(hm *Messenger) { hm.MessageReceived(hm, userID, m) }
+				}
+
+			case EventTypeDelivery:
+				if msng.DeliveryReceived != nil {
+					d := *msg.Delivery
+					handler = func(hm *Messenger) { hm.DeliveryReceived(hm, userID, d) }
+				}
 
-			case msg.Delivery != nil && msng.DeliveryReceived != nil:
-				go msng.DeliveryReceived(msng, userID, *msg.Delivery)
+			case EventTypePostback:
+				if msng.PostbackReceived != nil {
+					p := *msg.Postback
+					handler = func(hm *Messenger) { hm.PostbackReceived(hm, userID, p) }
+				}
 
-			case msg.Postback != nil && msng.PostbackReceived != nil:
-				go msng.PostbackReceived(msng, userID, *msg.Postback)
+			case EventTypeOptin:
+				if msng.OTNStore != nil && msg.Optin.OneTimeNotifToken != "" {
+					go hm.saveOTNToken(context.WithoutCancel(r.Context()), userID, *msg.Optin)
+				}
+				if msng.OptinReceived != nil {
+					o := *msg.Optin
+					handler = func(hm *Messenger) { hm.OptinReceived(hm, userID, o) }
+				}
 
-			case msg.Optin != nil && msng.OptinReceived != nil:
-				go msng.OptinReceived(msng, userID, *msg.Optin)
+			case EventTypeRead:
+				if msng.ReadReceived != nil {
+					rd := *msg.Read
+					handler = func(hm *Messenger) { hm.ReadReceived(hm, userID, rd) }
+				}
 
-			case msg.Read != nil && msng.ReadReceived != nil:
-				go msng.ReadReceived(msng, userID, *msg.Read)
+			case EventTypePassThreadControl:
+				if msng.PassThreadControlReceived != nil {
+					e := *msg.PassThreadControl
+					handler = func(hm *Messenger) { hm.PassThreadControlReceived(hm, userID, e) }
+				}
+
+			case EventTypeRequestThreadControl:
+				if msng.RequestThreadControlReceived != nil {
+					e := *msg.RequestThreadControl
+					handler = func(hm *Messenger) { hm.RequestThreadControlReceived(hm, userID, e) }
+				}
 			}
+			hm.dispatchEvent(r.Context(), eventType, msg, handler)
+			msng.logEvent(string(eventType), userID)
+			msng.metrics().ObserveWebhookEvent(string(eventType))
+
+			if msng.EventLog != nil {
+				e := WebhookEvent{
+					Timestamp:   time.UnixMilli(int64(msg.Timestamp)),
+					SenderID:    userID,
+					RecipientID: msg.Recipient.ID,
+					Type:        string(eventType),
+				}
+				if i < len(raw) && j < len(raw[i]) {
+					e.RawJSON = raw[i][j]
+				}
+				msng.recordEvent(r.Context(), e)
+			}
+		}
+
+		for _, msg := range entry.Standby {
+			msng.logEvent(string(EventTypeStandby), msg.Sender.ID)
+			msng.metrics().ObserveWebhookEvent(string(EventTypeStandby))
 		}
 	}
 }
@@ -133,24 +528,92 @@ func (msng Messenger) VerifyWebhook(w http.ResponseWriter, r *http.Request) {
 // Usually you don't have to use DecodeRequest if you setup events for specific types
 func DecodeRequest(r *http.Request) (FacebookRequest, error) {
 	defer r.Body.Close()
+	return decodeRequest(json.NewDecoder(r.Body))
+}
+
+// DecodeRequestStrict decodes request like DecodeRequest, but fails on fields not known to the package.
+// Use it during development to find events and fields the package doesn't support. Attachment payloads
+// are decoded by attachment type and their fields are not checked
+func DecodeRequestStrict(r *http.Request) (FacebookRequest, error) {
+	defer r.Body.Close()
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return FacebookRequest{}, err
+	}
+
+	// Entry.UnmarshalJSON and MessagingEntry.UnmarshalJSON don't inherit DisallowUnknownFields,
+	// so request is checked with entries and events decoded without them first
+	var strict struct {
+		Entry []struct {
+			rawEntry
+			Messaging []messagingFields `json:"messaging"`
+			Standby   []messagingFields `json:"standby"`
+		} `json:"entry"`
+		Object string `json:"object"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&strict); err != nil {
+		return FacebookRequest{}, err
+	}
+	return decodeRequest(json.NewDecoder(bytes.NewReader(b)))
+}
+
+func decodeRequest(dec *json.Decoder) (FacebookRequest, error) {
 	var fbRq FacebookRequest
-	err := json.NewDecoder(r.Body).Decode(&fbRq)
+	err := dec.Decode(&fbRq)
 	return fbRq, err
 }
 
+// Validate checks if request received on webhook is page event with at least one entry,
+// each entry must contain messaging events or changes
+func (r FacebookRequest) Validate() error {
+	if r.Object != "page" {
+		return fmt.Errorf("%w: object is %q", ErrInvalidRequest, r.Object)
+	}
+	if len(r.Entry) == 0 {
+		return fmt.Errorf("%w: no entries", ErrInvalidRequest)
+	}
+	for i, entry := range r.Entry {
+		if len(entry.Messaging) == 0 && len(entry.Standby) == 0 && len(entry.Changes) == 0 {
+			return fmt.Errorf("%w: entry %d has no messaging events or changes", ErrInvalidRequest, i)
+		}
+	}
+	return nil
+}
+
+// decodeError responds to webhook request that can't be decoded or isn't valid
+func (msng *Messenger) decodeError(w http.ResponseWriter, r *http.Request, err error) {
+	msng.logger().Error("invalid webhook request", "error", err)
+	if msng.OnDecodeError != nil {
+		msng.OnDecodeError(w, r, err)
+		return
+	}
+	writeJSONError(w, http.StatusBadRequest, err)
+}
+
+// writeJSONError responds with status code and error message as JSON
+func writeJSONError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
 // decodeResponse decodes Facebook response after sending message, usually contains MessageID or Error
 func decodeResponse(r *http.Response) (FacebookResponse, error) {
 	defer r.Body.Close()
-	var fbResp rawFBResponse
-	err := json.NewDecoder(r.Body).Decode(&fbResp)
+	b, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		return FacebookResponse{}, err
 	}
-
-	if fbResp.Error != nil {
-		return FacebookResponse{}, fbResp.Error.Error()
+	if err := responseError(r.StatusCode, b); err != nil {
+		return FacebookResponse{}, err
 	}
 
+	var fbResp rawFBResponse
+	if err := json.Unmarshal(b, &fbResp); err != nil {
+		return FacebookResponse{}, err
+	}
 	return FacebookResponse{
 		MessageID:   fbResp.MessageID,
 		RecipientID: fbResp.RecipientID,
@@ -1,16 +1,20 @@
 package messenger_test
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/mileusna/facebook-messenger"
+	"github.com/mileusna/facebook-messenger/messengertest"
 )
 
-var fs *httptest.Server
+// fb mocks up fb messenger server
+var fb *messengertest.MockFacebookServer
 
 var ts *httptest.Server
 
@@ -19,19 +23,11 @@ const (
 )
 
 func TestMain(m *testing.M) {
-	// fs will mock up fb messenger server
-	fs = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		rec := messenger.FacebookResponse{
-			RecipientID: 12123213123,
-			MessageID:   "mid00000TEST00000TEST00000TEST",
-		}
-		b, _ := json.Marshal(rec)
-		w.Write(b)
-	}))
-	defer fs.Close()
+	fb = messengertest.NewMockFacebookServer()
+	defer fb.Close()
 
 	// setup chatbot
-	messenger.TestURL = fs.URL + "/"
+	messenger.TestURL = fb.URL()
 
 	msng := &messenger.Messenger{
 		AccessToken: "XXXXXXX",
@@ -52,10 +48,103 @@ func TestMain(m *testing.M) {
 func TestVerify(t *testing.T) {
 	challenge := "1122334455"
 	verifyReq := ts.URL + "/?test=1&hub.mode=subscribe&hub.challenge=" + challenge + "&hub.verify_token=" + verifyToken
-	resp, _ := http.Get(verifyReq)
+	resp, err := http.Get(verifyReq)
+	if err != nil {
+		t.Fatal(err)
+	}
 	defer resp.Body.Close()
 	s, _ := ioutil.ReadAll(resp.Body)
 	if string(s) != challenge {
 		t.Error("Challenge failed, expected", challenge, "returned", string(s))
 	}
 }
+
+// mockServer starts mock FB server with handler h for the test, pass returned option to messenger.New
+func mockServer(t *testing.T, h http.HandlerFunc) messenger.Option {
+	s := httptest.NewServer(h)
+	t.Cleanup(s.Close)
+	return messenger.WithTestURL(s.URL)
+}
+
+func TestSendTextMessageStr(t *testing.T) {
+	fb.Reset()
+	msng := messenger.New("XXXXXXX", "")
+	if _, err := msng.SendTextMessageStr(context.Background(), "1254477777772919", "hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	call, _ := fb.LastCall()
+	expected := `{"message":{"text":"hello"},"recipient":{"id":"1254477777772919"}}`
+	if call.Endpoint != "me/messages" || string(call.Body) != expected {
+		t.Error("Expected", expected, "sent", call.Endpoint, string(call.Body))
+	}
+}
+
+func TestSendTextMessageReply(t *testing.T) {
+	fb.Reset()
+	fb.RespondWith("me/messages", http.StatusOK, messenger.FacebookResponse{RecipientID: 1254477777772919, MessageID: "mid.2"})
+
+	msng := messenger.New("XXXXXXX", "")
+	resp, err := msng.SendTextMessageReply(context.Background(), "1254477777772919", "mid.1", "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.MessageID != "mid.2" {
+		t.Error("Expected message ID mid.2, returned", resp.MessageID)
+	}
+
+	call, _ := fb.LastCall()
+	expected := `{"message":{"text":"hello","reply_to":{"mid":"mid.1"}},"recipient":{"id":"1254477777772919"}}`
+	if string(call.Body) != expected {
+		t.Error("Expected", expected, "sent", string(call.Body))
+	}
+}
+
+func TestReceiveReply(t *testing.T) {
+	var m messenger.FacebookMessage
+	if err := json.Unmarshal([]byte(`{"mid":"mid.2","text":"yes","reply_to":{"mid":"mid.1"}}`), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.ReplyTo == nil || m.ReplyTo.Mid != "mid.1" {
+		t.Error("Reply not decoded", m)
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+	tokens := make(chan string, 2)
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		tokens <- r.FormValue("access_token")
+		w.Write([]byte(`{"recipient_id":"1","message_id":"mid.1"}`))
+	})
+
+	page1Queue, page2Queue := messenger.NewMemoryRetryQueue(), messenger.NewMemoryRetryQueue()
+	base := messenger.New("PAGE1_TOKEN", "1", mock, messenger.WithOptOutKeywords([]string{"stop"}),
+		messenger.WithRetryQueue(page1Queue), messenger.WithBlockList(messenger.NewMemoryBlockList()))
+	base.MessageReceived = func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) {}
+
+	pages := map[string]messenger.Messenger{
+		"1": base,
+		"2": base.Clone(messenger.WithAccessToken("PAGE2_TOKEN"), messenger.WithPageID("2"), messenger.WithRetryQueue(page2Queue)),
+	}
+	if page2 := pages["2"]; page2.RetryQueue != page2Queue || page2.BlockList != nil {
+		t.Error("Expected page scoped components of page 1 not kept", page2.RetryQueue, page2.BlockList)
+	}
+	if samePage := base.Clone(messenger.WithSendTimeout(time.Second)); samePage.RetryQueue != page1Queue || samePage.BlockList == nil {
+		t.Error("Expected page scoped components kept for the same page", samePage.RetryQueue, samePage.BlockList)
+	}
+	for pageID, msng := range pages {
+		if msng.PageID != pageID || msng.MessageReceived == nil || len(msng.OptOutKeywords) != 1 {
+			t.Error("Unexpected messenger for page", pageID, msng.PageID)
+		}
+		if _, err := msng.SendTextMessageStr(context.Background(), "1", "hello"); err != nil {
+			t.Fatal(err)
+		}
+		if token, expected := <-tokens, "PAGE"+pageID+"_TOKEN"; token != expected {
+			t.Error("Expected", expected, "sent", token)
+		}
+	}
+	if base.AccessToken != "PAGE1_TOKEN" || base.PageID != "1" {
+		t.Error("Clone changed original messenger", base.AccessToken, base.PageID)
+	}
+}
@@ -0,0 +1,138 @@
+// Package messengertest provides mock Facebook Graph API server for testing bots built with messenger package.
+//
+//	fb := messengertest.NewMockFacebookServer()
+//	defer fb.Close()
+//	msng := messenger.New(accessToken, pageID, messenger.WithTestURL(fb.URL()))
+package messengertest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// RecordedCall is request received by MockFacebookServer
+type RecordedCall struct {
+	Method   string
+	Endpoint string // path without leading slash, e.g. "me/messages"
+	Query    url.Values
+	Body     []byte
+}
+
+type response struct {
+	statusCode int
+	body       []byte
+}
+
+// MockFacebookServer simulates Graph API endpoints used by messenger:
+// me/messages, me/messenger_profile and <page_id>/subscribed_apps
+type MockFacebookServer struct {
+	srv *httptest.Server
+
+	mu        sync.Mutex
+	calls     []RecordedCall
+	responses map[string]response
+}
+
+// NewMockFacebookServer starts new mock server, call Close when done
+func NewMockFacebookServer() *MockFacebookServer {
+	s := &MockFacebookServer{responses: map[string]response{}}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns base URL of the server, pass it to messenger.WithTestURL
+func (s *MockFacebookServer) URL() string {
+	return s.srv.URL + "/"
+}
+
+// Close stops the server
+func (s *MockFacebookServer) Close() {
+	s.srv.Close()
+}
+
+// RespondWith sets response for endpoint (e.g. "me/messages"). Body is sent as is if it is string or []byte,
+// otherwise it is JSON encoded
+func (s *MockFacebookServer) RespondWith(endpoint string, statusCode int, body interface{}) {
+	var b []byte
+	switch body := body.(type) {
+	case string:
+		b = []byte(body)
+	case []byte:
+		b = body
+	default:
+		b, _ = json.Marshal(body)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[strings.TrimPrefix(endpoint, "/")] = response{statusCode, b}
+}
+
+// Calls returns all requests received by the server
+func (s *MockFacebookServer) Calls() []RecordedCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedCall(nil), s.calls...)
+}
+
+// LastCall returns last request received by the server, ok is false if there were no requests
+func (s *MockFacebookServer) LastCall() (call RecordedCall, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.calls) == 0 {
+		return RecordedCall{}, false
+	}
+	return s.calls[len(s.calls)-1], true
+}
+
+// Reset clears recorded calls and configured responses
+func (s *MockFacebookServer) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+	s.responses = map[string]response{}
+}
+
+func (s *MockFacebookServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	endpoint := strings.TrimPrefix(r.URL.Path, "/")
+
+	s.mu.Lock()
+	s.calls = append(s.calls, RecordedCall{
+		Method:   r.Method,
+		Endpoint: endpoint,
+		Query:    r.URL.Query(),
+		Body:     body,
+	})
+	resp, ok := s.responses[endpoint]
+	s.mu.Unlock()
+
+	if !ok {
+		resp = defaultResponse(r.Method, endpoint)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.statusCode)
+	w.Write(resp.body)
+}
+
+// defaultResponse returns successful response of simulated endpoints, Graph API error for unknown endpoints
+func defaultResponse(method, endpoint string) response {
+	switch {
+	case endpoint == "me/messages":
+		return response{http.StatusOK, []byte(`{"recipient_id":"1254477777772919","message_id":"mid.1"}`)}
+	case endpoint == "me/messenger_profile" && method == http.MethodGet:
+		return response{http.StatusOK, []byte(`{"data":[]}`)}
+	case endpoint == "me/messenger_profile":
+		return response{http.StatusOK, []byte(`{"result":"success"}`)}
+	case strings.HasSuffix(endpoint, "/subscribed_apps") && method == http.MethodGet:
+		return response{http.StatusOK, []byte(`{"data":[]}`)}
+	case strings.HasSuffix(endpoint, "/subscribed_apps"):
+		return response{http.StatusOK, []byte(`{"success":true}`)}
+	}
+	return response{http.StatusBadRequest, []byte(`{"error":{"message":"Unknown path components: /` + endpoint + `","type":"OAuthException","code":2500}}`)}
+}
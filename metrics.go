@@ -0,0 +1,37 @@
+package messenger
+
+import "time"
+
+// MetricsRecorder collects metrics of sent messages and received webhook events.
+// See metrics/prometheus package for Prometheus implementation
+type MetricsRecorder interface {
+	// ObserveSend is called after each sent message, err is nil if message was sent
+	ObserveSend(d time.Duration, err error)
+
+	// ObserveWebhookEvent is called for each received webhook event
+	ObserveWebhookEvent(eventType string)
+
+	// ObserveWebhookProcessing is called after webhook request is processed
+	ObserveWebhookProcessing(d time.Duration)
+}
+
+// WithMetrics sets metrics recorder
+func WithMetrics(m MetricsRecorder) Option {
+	return func(msng *Messenger) {
+		msng.Metrics = m
+	}
+}
+
+// nopMetrics is used when Metrics is not set
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveSend(d time.Duration, err error)   {}
+func (nopMetrics) ObserveWebhookEvent(eventType string)     {}
+func (nopMetrics) ObserveWebhookProcessing(d time.Duration) {}
+
+func (msng *Messenger) metrics() MetricsRecorder {
+	if msng.Metrics == nil {
+		return nopMetrics{}
+	}
+	return msng.Metrics
+}
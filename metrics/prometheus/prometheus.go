@@ -0,0 +1,85 @@
+// Package prometheus exposes facebook-messenger metrics to Prometheus. It is a separate package, so
+// Prometheus client github.com/prometheus/client_golang is needed only by bots that import it
+//
+//	metrics := prometheus.NewPrometheusMetrics(nil, "mybot")
+//	msng := messenger.New(accessToken, pageID, messenger.WithMetrics(metrics))
+package prometheus
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusMetrics implements messenger.MetricsRecorder with Prometheus counters and histograms
+type PrometheusMetrics struct {
+	sends              *prom.CounterVec
+	webhookEvents      *prom.CounterVec
+	sendDuration       prom.Histogram
+	processingDuration prom.Histogram
+}
+
+// NewPrometheusMetrics creates and registers messenger metrics with reg, prometheus.DefaultRegisterer is used if reg is nil.
+// Metric names are prefixed with namespace if not empty
+func NewPrometheusMetrics(reg prom.Registerer, namespace string) *PrometheusMetrics {
+	if reg == nil {
+		reg = prom.DefaultRegisterer
+	}
+
+	m := &PrometheusMetrics{
+		sends: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "messenger_sends_total",
+			Help:      "Number of messages sent to Facebook Messenger by status and Facebook error code.",
+		}, []string{"status", "code"}),
+		webhookEvents: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "messenger_webhook_events_total",
+			Help:      "Number of webhook events received from Facebook by type.",
+		}, []string{"type"}),
+		sendDuration: prom.NewHistogram(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "messenger_send_duration_seconds",
+			Help:      "Duration of send message API calls.",
+			Buckets:   prom.DefBuckets,
+		}),
+		processingDuration: prom.NewHistogram(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "messenger_webhook_processing_duration_seconds",
+			Help:      "Duration of webhook request processing.",
+			Buckets:   prom.DefBuckets,
+		}),
+	}
+
+	reg.MustRegister(m.sends, m.webhookEvents, m.sendDuration, m.processingDuration)
+	return m
+}
+
+// ObserveSend implements messenger.MetricsRecorder
+func (m *PrometheusMetrics) ObserveSend(d time.Duration, err error) {
+	m.sendDuration.Observe(d.Seconds())
+	if err == nil {
+		m.sends.WithLabelValues("success", "").Inc()
+		return
+	}
+
+	code := ""
+	var apiErr messenger.FacebookAPIError
+	if errors.As(err, &apiErr) {
+		code = strconv.Itoa(apiErr.Code)
+	}
+	m.sends.WithLabelValues("error", code).Inc()
+}
+
+// ObserveWebhookEvent implements messenger.MetricsRecorder
+func (m *PrometheusMetrics) ObserveWebhookEvent(eventType string) {
+	m.webhookEvents.WithLabelValues(eventType).Inc()
+}
+
+// ObserveWebhookProcessing implements messenger.MetricsRecorder
+func (m *PrometheusMetrics) ObserveWebhookProcessing(d time.Duration) {
+	m.processingDuration.Observe(d.Seconds())
+}
@@ -0,0 +1,64 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	reg := prom.NewRegistry()
+	m := NewPrometheusMetrics(reg, "mybot")
+
+	m.ObserveSend(time.Millisecond, nil)
+	m.ObserveSend(time.Millisecond, messenger.FacebookAPIError{Code: 551})
+	m.ObserveSend(time.Millisecond, errors.New("connection refused"))
+	m.ObserveWebhookEvent("message")
+	m.ObserveWebhookEvent("message")
+	m.ObserveWebhookProcessing(time.Millisecond)
+
+	tests := []struct {
+		counter prom.Collector
+		value   float64
+	}{
+		{m.sends.WithLabelValues("success", ""), 1},
+		{m.sends.WithLabelValues("error", "551"), 1},
+		{m.sends.WithLabelValues("error", ""), 1},
+		{m.webhookEvents.WithLabelValues("message"), 2},
+	}
+	for i, tt := range tests {
+		if v := testutil.ToFloat64(tt.counter); v != tt.value {
+			t.Error(i, "Expected", tt.value, "counted", v)
+		}
+	}
+
+	for _, name := range []string{"mybot_messenger_send_duration_seconds", "mybot_messenger_webhook_processing_duration_seconds"} {
+		if n, err := testutil.GatherAndCount(reg, name); err != nil || n != 1 {
+			t.Error("Expected histogram", name, "registered", n, err)
+		}
+	}
+}
+
+func TestPrometheusMetricsWithMessenger(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"This person isn't available right now.","type":"OAuthException","code":551}}`))
+	}))
+	defer s.Close()
+
+	m := NewPrometheusMetrics(prom.NewRegistry(), "")
+	msng := messenger.New("XXXXXXX", "", messenger.WithTestURL(s.URL), messenger.WithMetrics(m))
+	if _, err := msng.SendTextMessageStr(context.Background(), "1", "hello"); err == nil {
+		t.Fatal("Expected send error")
+	}
+	if v := testutil.ToFloat64(m.sends.WithLabelValues("error", "551")); v != 1 {
+		t.Error("Expected failed send counted with Facebook error code, counted", v)
+	}
+}
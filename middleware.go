@@ -0,0 +1,160 @@
+package messenger
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EventMiddleware is called before event handler for every messaging event received on webhook.
+// Middleware calls next to continue with next middleware or event handler, or returns without calling it
+// to stop processing of the event
+type EventMiddleware func(eventType EventType, userID string, entry MessagingEntry, next func())
+
+// Use adds event middleware, middleware is called in order it is added
+func (msng *Messenger) Use(mw ...EventMiddleware) {
+	msng.middleware = append(msng.middleware, mw...)
+}
+
+// dispatchEvent runs event handler in new goroutine through middleware chain,
+// middleware is called even if there is no handler for the event
+func (msng *Messenger) dispatchEvent(ctx context.Context, eventType EventType, entry MessagingEntry, handler func(hm *Messenger)) {
+	userID := strconv.FormatInt(entry.Sender.ID, 10)
+	if handler == nil && len(msng.middleware) == 0 {
+		return
+	}
+	var turn *lockTurn
+	if handler != nil && msng.ConversationLock != nil {
+		// turn is taken here, not in handler goroutine, so handlers of the same user run in order events were received
+		turn = msng.ConversationLock.enqueue(userID)
+		h := handler
+		handler = func(hm *Messenger) {
+			msng.waitTurn(turn)
+			h(hm)
+		}
+	}
+
+	// handlers run after webhook request is done, so event context is not cancelled with the request
+	ev := &eventScope{msng: msng, ctx: context.WithValue(context.WithoutCancel(ctx), eventMessengerKey{}, msng)}
+	entry.event = ev
+	chain := func() {
+		if handler != nil {
+			hm := *msng
+			hm.eventCtx = ev.ctx
+			handler(&hm)
+		}
+	}
+	for i := len(msng.middleware) - 1; i >= 0; i-- {
+		mw, next := msng.middleware[i], chain
+		chain = func() { mw(eventType, userID, entry, next) }
+	}
+	run := chain
+	if turn != nil {
+		// turn is released even if middleware stops the event before handler
+		run = func() {
+			defer turn.unlock()
+			chain()
+		}
+	}
+	if !msng.goHandler(ctx, eventType, run) && turn != nil {
+		turn.unlock()
+	}
+}
+
+// eventScope is state of event shared by middleware and event handler
+type eventScope struct {
+	msng *Messenger      // messenger handling the event
+	ctx  context.Context // event context, middleware like LocaleDetectMiddleware adds values to it
+}
+
+// eventMessengerKey is key of messenger handling event in event context, middleware like ProfileCache
+// use it to call API with the page the event was received for
+type eventMessengerKey struct{}
+
+// EventContext returns context of event handled by event handler, with values added by event middleware.
+// Outside of event handlers background context is returned
+func (msng *Messenger) EventContext() context.Context {
+	if msng.eventCtx == nil {
+		return context.Background()
+	}
+	return msng.eventCtx
+}
+
+// ConversationLockMiddleware returns middleware that processes events of the same user one at a time,
+// so event handlers are never run concurrently for the same user. Unlike WithConversationLock,
+// middleware added after it runs under the lock too
+func ConversationLockMiddleware() EventMiddleware {
+	cl := NewConversationLock(0)
+	return func(eventType EventType, userID string, entry MessagingEntry, next func()) {
+		t := cl.enqueue(userID)
+		defer t.unlock()
+		if entry.event != nil {
+			entry.event.msng.waitTurn(t)
+		} else {
+			<-t.ready
+		}
+		next()
+	}
+}
+
+// UserRateLimitMiddleware returns middleware that drops events of users who send more than rps events per second.
+// It panics if rps is not greater than zero
+func UserRateLimitMiddleware(rps float64) EventMiddleware {
+	if !(rps > 0) {
+		panic(fmt.Sprintf("messenger: invalid user rate limit %v", rps))
+	}
+	interval := time.Duration(float64(time.Second) / rps)
+	// users whose last event is older than interval are removed every sweepInterval, so map doesn't grow
+	// with every user ever seen
+	sweepInterval := interval
+	if sweepInterval < time.Minute {
+		sweepInterval = time.Minute
+	}
+
+	var mu sync.Mutex
+	last := map[string]time.Time{} // time of last processed event per user
+	lastSweep := time.Now()
+	return func(eventType EventType, userID string, entry MessagingEntry, next func()) {
+		now := time.Now()
+		mu.Lock()
+		if now.Sub(lastSweep) >= sweepInterval {
+			for u, t := range last {
+				if now.Sub(t) >= interval {
+					delete(last, u)
+				}
+			}
+			lastSweep = now
+		}
+		if t, ok := last[userID]; ok && now.Sub(t) < interval {
+			mu.Unlock()
+			return
+		}
+		last[userID] = now
+		mu.Unlock()
+		next()
+	}
+}
+
+type localeKey struct{}
+
+// LocaleDetectMiddleware returns middleware that adds user's locale from cache to event context,
+// handlers read it with LocaleFromContext(msng.EventContext()). If profile can't be fetched, event is
+// processed without locale
+func LocaleDetectMiddleware(cache CachedProfileStore) EventMiddleware {
+	return func(eventType EventType, userID string, entry MessagingEntry, next func()) {
+		if ev := entry.event; ev != nil {
+			if p, err := cache.Profile(ev.ctx, userID); err == nil && p.Locale != "" {
+				ev.ctx = context.WithValue(ev.ctx, localeKey{}, p.Locale)
+			}
+		}
+		next()
+	}
+}
+
+// LocaleFromContext returns user's locale added to event context by LocaleDetectMiddleware
+func LocaleFromContext(ctx context.Context) (string, bool) {
+	locale, ok := ctx.Value(localeKey{}).(string)
+	return locale, ok
+}
@@ -0,0 +1,119 @@
+package messenger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestEventMiddleware(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	record := func(s string) {
+		mu.Lock()
+		calls = append(calls, s)
+		mu.Unlock()
+	}
+
+	done := make(chan struct{})
+	msng := messenger.New("XXXXXXX", "1")
+	msng.MessageReceived = func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) {
+		record("handler")
+		close(done)
+	}
+	msng.Use(
+		func(eventType messenger.EventType, userID string, entry messenger.MessagingEntry, next func()) {
+			if eventType != messenger.EventTypeMessage {
+				return // stop read event
+			}
+			if userID != "12123213123" {
+				t.Error("Unexpected user", userID)
+			}
+			record("first")
+			next()
+		},
+		messenger.ConversationLockMiddleware(),
+		func(eventType messenger.EventType, userID string, entry messenger.MessagingEntry, next func()) {
+			record("second")
+			next()
+		},
+	)
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage)))
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(calls, ",") != "first,second,handler" {
+		t.Error("Unexpected order", calls)
+	}
+}
+
+func TestUserRateLimitMiddleware(t *testing.T) {
+	mw := messenger.UserRateLimitMiddleware(1)
+	processed := 0
+	next := func() { processed++ }
+	for i := 0; i < 3; i++ {
+		mw(messenger.EventTypeMessage, "1", messenger.MessagingEntry{}, next)
+	}
+	mw(messenger.EventTypeMessage, "2", messenger.MessagingEntry{}, next)
+	if processed != 2 {
+		t.Error("Expected first event of each user processed, processed", processed)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for zero rate limit")
+		}
+	}()
+	messenger.UserRateLimitMiddleware(0)
+}
+
+func TestLocaleDetectMiddleware(t *testing.T) {
+	t.Parallel()
+	var fetched int32
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetched, 1)
+		if r.URL.Path != "/12123213123" || !strings.Contains(r.FormValue("fields"), "locale") {
+			t.Error("Unexpected request", r.URL)
+		}
+		if r.FormValue("access_token") == "PAGE2_TOKEN" {
+			w.Write([]byte(`{"id":"12123213123","first_name":"Jean","locale":"fr_FR"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"12123213123","first_name":"John","locale":"de_DE"}`))
+	})
+
+	locales := make(chan string, 2)
+	msng := messenger.New("XXXXXXX", "1", mock)
+	msng.MessageReceived = func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) {
+		locale, _ := messenger.LocaleFromContext(msng.EventContext())
+		locales <- locale
+	}
+	msng.Use(messenger.LocaleDetectMiddleware(messenger.NewProfileCache(&msng, 0)))
+	for i := 0; i < 2; i++ {
+		msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage)))
+		if locale := <-locales; locale != "de_DE" {
+			t.Error("Expected de_DE, returned", locale)
+		}
+	}
+	// read events go through middleware too, profile is fetched only once
+	if n := atomic.LoadInt32(&fetched); n != 1 {
+		t.Error("Expected profile fetched once, fetched", n)
+	}
+
+	if _, ok := messenger.LocaleFromContext(msng.EventContext()); ok {
+		t.Error("Expected no locale outside of event handler")
+	}
+
+	// clone for other page shares the cache, but fetches profiles of its users with its own token
+	page2 := msng.Clone(messenger.WithAccessToken("PAGE2_TOKEN"), messenger.WithPageID("2"))
+	page2.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage)))
+	if locale := <-locales; locale != "fr_FR" {
+		t.Error("Expected fr_FR, returned", locale)
+	}
+}
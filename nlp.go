@@ -0,0 +1,74 @@
+package messenger
+
+// MessageNLP contains built-in NLP results Facebook adds to received messages when NLP is enabled for the page
+type MessageNLP struct {
+	Intents  []NLPIntent            `json:"intents"`
+	Entities map[string][]NLPEntity `json:"entities"`
+}
+
+// NLPIntent is intent detected in message text
+type NLPIntent struct {
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+}
+
+// NLPEntity is entity detected in message text
+type NLPEntity struct {
+	Value      interface{} `json:"value"`
+	Confidence float64     `json:"confidence"`
+}
+
+// TopIntent returns intent with the highest confidence, ok is false if there are no intents.
+// Older NLP format, where intents are sent as "intent" entity, is also supported
+func (nlp MessageNLP) TopIntent() (intent NLPIntent, ok bool) {
+	for _, i := range nlp.Intents {
+		if !ok || i.Confidence > intent.Confidence {
+			intent, ok = i, true
+		}
+	}
+	for _, e := range nlp.Entities["intent"] {
+		name, _ := e.Value.(string)
+		if !ok || e.Confidence > intent.Confidence {
+			intent, ok = NLPIntent{Name: name, Confidence: e.Confidence}, true
+		}
+	}
+	return intent, ok
+}
+
+// NLPFilter passes received message to MessageReceived only if its top NLP intent is confident enough,
+// other messages are passed to FallbackHandler. Messages without NLP results are always passed to MessageReceived
+type NLPFilter struct {
+	MinConfidence   float64
+	Intent          string // if set, top intent must also match Intent
+	FallbackHandler func(msng *Messenger, userID int64, m FacebookMessage)
+}
+
+// WithNLPFilter wraps MessageReceived handler with NLP filter f, set MessageReceived before calling it
+func (msng *Messenger) WithNLPFilter(f NLPFilter) {
+	next := msng.MessageReceived
+	msng.MessageReceived = func(msng *Messenger, userID int64, m FacebookMessage) {
+		if f.accepts(m) {
+			if next != nil {
+				next(msng, userID, m)
+			}
+			return
+		}
+		if f.FallbackHandler != nil {
+			f.FallbackHandler(msng, userID, m)
+		}
+	}
+}
+
+func (f NLPFilter) accepts(m FacebookMessage) bool {
+	if m.NLP == nil {
+		return true
+	}
+	intent, ok := m.NLP.TopIntent()
+	if !ok {
+		return true
+	}
+	if f.Intent != "" && intent.Name != f.Intent {
+		return false
+	}
+	return intent.Confidence >= f.MinConfidence
+}
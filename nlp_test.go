@@ -0,0 +1,45 @@
+package messenger_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestNLPFilter(t *testing.T) {
+	var handled, fallback []string
+	msng := messenger.New("XXXXXXX", "")
+	msng.MessageReceived = func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) {
+		handled = append(handled, m.Mid)
+	}
+	msng.WithNLPFilter(messenger.NLPFilter{
+		MinConfidence: 0.8,
+		Intent:        "greeting",
+		FallbackHandler: func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) {
+			fallback = append(fallback, m.Mid)
+		},
+	})
+
+	messages := []string{
+		`{"mid":"plain","text":"hi"}`,
+		`{"mid":"confident","nlp":{"intents":[{"name":"greeting","confidence":0.95}]}}`,
+		`{"mid":"unsure","nlp":{"intents":[{"name":"greeting","confidence":0.5}]}}`,
+		`{"mid":"other","nlp":{"intents":[{"name":"bye","confidence":0.99},{"name":"greeting","confidence":0.9}]}}`,
+		`{"mid":"legacy","nlp":{"entities":{"intent":[{"value":"greeting","confidence":0.85}]}}}`,
+	}
+	for _, s := range messages {
+		var m messenger.FacebookMessage
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			t.Fatal(err)
+		}
+		msng.MessageReceived(&msng, 1, m)
+	}
+
+	if len(handled) != 3 || handled[0] != "plain" || handled[1] != "confident" || handled[2] != "legacy" {
+		t.Error("Unexpected handled messages", handled)
+	}
+	if len(fallback) != 2 || fallback[0] != "unsure" || fallback[1] != "other" {
+		t.Error("Unexpected fallback messages", fallback)
+	}
+}
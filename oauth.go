@@ -0,0 +1,105 @@
+package messenger
+
+import (
+	"context"
+	"crypto/hmac"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const oauthDialogURL = "https://www.facebook.com/dialog/oauth"
+
+// OAuthHelper handles Facebook Login redirects, used for linking bot users with your accounts
+type OAuthHelper struct {
+	AppID      string
+	AppSecret  string
+	HttpClient *http.Client // http.DefaultClient if nil
+
+	baseURL string // Graph API base URL of messenger helper is created for
+}
+
+// OAuthHelper returns OAuthHelper for messenger's app, it calls Graph API with messenger's HTTP client and API version
+func (msng *Messenger) OAuthHelper() OAuthHelper {
+	return OAuthHelper{
+		AppID:      msng.AppID,
+		AppSecret:  msng.AppSecret,
+		HttpClient: msng.GetClient(),
+		baseURL:    msng.baseURL(),
+	}
+}
+
+// TokenResponse is user access token received for Facebook Login code
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// GenerateAuthURL returns Facebook Login dialog URL user should be redirected to.
+// Facebook redirects user back to redirectURI with code and state, check state with ValidateRedirectState.
+// If pageID is not empty, it is added to redirectURI as page_id parameter, so redirect handler knows which
+// page user is linking from. Use PageRedirectURI to get redirect URI for ExchangeCodeForToken in that case
+func (h OAuthHelper) GenerateAuthURL(pageID, redirectURI, state string, scopes []string) string {
+	q := url.Values{
+		"client_id":     {h.AppID},
+		"redirect_uri":  {PageRedirectURI(pageID, redirectURI)},
+		"state":         {state},
+		"response_type": {"code"},
+	}
+	if len(scopes) > 0 {
+		q.Set("scope", strings.Join(scopes, ","))
+	}
+	return oauthDialogURL + "?" + q.Encode()
+}
+
+// PageRedirectURI returns redirectURI with page_id parameter used by GenerateAuthURL for pageID,
+// redirectURI is returned unchanged if pageID is empty or redirectURI is not valid URL
+func PageRedirectURI(pageID, redirectURI string) string {
+	if pageID == "" {
+		return redirectURI
+	}
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return redirectURI
+	}
+	q := u.Query()
+	q.Set("page_id", pageID)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// ExchangeCodeForToken exchanges code received on redirectURI for user access token.
+// redirectURI must be the same one used for GenerateAuthURL
+func (h OAuthHelper) ExchangeCodeForToken(ctx context.Context, code, redirectURI string) (TokenResponse, error) {
+	q := url.Values{
+		"client_id":     {h.AppID},
+		"client_secret": {h.AppSecret},
+		"redirect_uri":  {redirectURI},
+		"code":          {code},
+	}
+	c := h.HttpClient
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	baseURL := h.baseURL
+	if baseURL == "" {
+		baseURL = graphBaseURL()
+	}
+
+	var t TokenResponse
+	u := baseURL + "oauth/access_token?" + q.Encode()
+	if err := doGraphRequest(ctx, c, http.MethodPost, u, nil, &t); err != nil {
+		return TokenResponse{}, err
+	}
+	return t, nil
+}
+
+// ValidateRedirectState checks state received on redirect against expected state in constant time
+func ValidateRedirectState(received, expected string) bool {
+	if expected == "" {
+		return false
+	}
+	return hmac.Equal([]byte(received), []byte(expected))
+}
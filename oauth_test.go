@@ -0,0 +1,71 @@
+package messenger_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestOAuthHelper(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/oauth/access_token" {
+			t.Error("Unexpected request", r.Method, r.URL.Path)
+		}
+		if r.FormValue("code") != "CODE" || r.FormValue("client_secret") != "SECRET" || r.FormValue("redirect_uri") != "https://example.com/cb" {
+			t.Error("Unexpected token request", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"access_token":"USER_TOKEN","token_type":"bearer","expires_in":5183944}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	msng.AppID, msng.AppSecret = "APP_ID", "SECRET"
+	h := msng.OAuthHelper()
+
+	u, err := url.Parse(h.GenerateAuthURL("", "https://example.com/cb", "STATE", []string{"email", "public_profile"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if u.Host != "www.facebook.com" || u.Path != "/dialog/oauth" {
+		t.Error("Unexpected auth URL", u)
+	}
+	if q.Get("client_id") != "APP_ID" || q.Get("redirect_uri") != "https://example.com/cb" || q.Get("state") != "STATE" || q.Get("scope") != "email,public_profile" {
+		t.Error("Unexpected auth URL query", q)
+	}
+
+	token, err := h.ExchangeCodeForToken(context.Background(), "CODE", "https://example.com/cb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "USER_TOKEN" || token.TokenType != "bearer" || token.ExpiresIn != 5183944 {
+		t.Error("Unexpected token", token)
+	}
+}
+
+func TestGenerateAuthURLForPage(t *testing.T) {
+	h := messenger.OAuthHelper{AppID: "APP_ID"}
+	u, err := url.Parse(h.GenerateAuthURL("42", "https://example.com/cb?lang=en", "STATE", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	redirectURI := messenger.PageRedirectURI("42", "https://example.com/cb?lang=en")
+	if redirectURI != "https://example.com/cb?lang=en&page_id=42" || u.Query().Get("redirect_uri") != redirectURI {
+		t.Error("Expected page ID in redirect URI", redirectURI, u.Query().Get("redirect_uri"))
+	}
+	if u.Query().Get("scope") != "" {
+		t.Error("Expected no scope", u.Query().Get("scope"))
+	}
+}
+
+func TestValidateRedirectState(t *testing.T) {
+	if !messenger.ValidateRedirectState("abc", "abc") {
+		t.Error("Expected valid state")
+	}
+	if messenger.ValidateRedirectState("abd", "abc") || messenger.ValidateRedirectState("", "") {
+		t.Error("Expected invalid state")
+	}
+}
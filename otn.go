@@ -0,0 +1,26 @@
+package messenger
+
+// OneTimeNotifRequestMessage asks user for permission to send one follow up message outside of 24 hour window.
+// Requires Graph API v6.0 or newer, see WithAPIVersion
+type OneTimeNotifRequestMessage struct {
+	Message   genericMessageContent `json:"message"`
+	Recipient recipient             `json:"recipient"`
+}
+
+// NewOneTimeNotifRequest creates one time notification request for recipientID with title about the topic user is
+// notified about. Data is sent back as payload with the optin event when user agrees
+func NewOneTimeNotifRequest(recipientID, title, data string) OneTimeNotifRequestMessage {
+	return OneTimeNotifRequestMessage{
+		Recipient: newRecipient(recipientID),
+		Message: genericMessageContent{
+			Attachment: &attachment{
+				Type: string(AttachmentTypeTemplate),
+				Payload: payload{
+					TemplateType: string(TemplateTypeOneTimeNotifReq),
+					Title:        title,
+					Payload:      data,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package messenger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// OTNStore stores one time notification tokens received when user agrees to one time notification request.
+// Tokens are stored per user and tag, tag is payload of the request, see NewOneTimeNotifRequest.
+//
+// Tokens can be used only once and expire after one year, so persistent stores should expire them too.
+// Redis backed store can keep each token in key like "otn:<userID>:<tag>" with SET ... EX 31536000
+// and remove it with DEL after it's used
+type OTNStore interface {
+	SaveToken(ctx context.Context, userID, token, tag string) error
+	GetToken(ctx context.Context, userID, tag string) (token string, err error) // ErrNotFound if there is no token
+	DeleteToken(ctx context.Context, userID, tag string) error
+}
+
+// WithOTNStore sets store one time notification tokens from webhook events are saved to
+func WithOTNStore(s OTNStore) Option {
+	return func(msng *Messenger) {
+		msng.OTNStore = s
+	}
+}
+
+// MemoryOTNStore is OTNStore kept in memory
+type MemoryOTNStore struct {
+	tokens sync.Map
+}
+
+// NewMemoryOTNStore creates empty MemoryOTNStore
+func NewMemoryOTNStore() *MemoryOTNStore {
+	return &MemoryOTNStore{}
+}
+
+type otnKey struct {
+	userID, tag string
+}
+
+// SaveToken stores token for user and tag, replacing previous token
+func (s *MemoryOTNStore) SaveToken(ctx context.Context, userID, token, tag string) error {
+	s.tokens.Store(otnKey{userID, tag}, token)
+	return nil
+}
+
+// GetToken returns token for user and tag, ErrNotFound if there is no token
+func (s *MemoryOTNStore) GetToken(ctx context.Context, userID, tag string) (string, error) {
+	token, ok := s.tokens.Load(otnKey{userID, tag})
+	if !ok {
+		return "", ErrNotFound
+	}
+	return token.(string), nil
+}
+
+// DeleteToken removes token for user and tag
+func (s *MemoryOTNStore) DeleteToken(ctx context.Context, userID, tag string) error {
+	s.tokens.Delete(otnKey{userID, tag})
+	return nil
+}
+
+// OTNTokenRecipient is user identified by one time notification token, see SendStoredOTNMessage
+type OTNTokenRecipient struct {
+	Token string `json:"one_time_notif_token"`
+}
+
+func (r OTNTokenRecipient) recipientJSON() ([]byte, error) { return json.Marshal(r) }
+
+// SendStoredOTNMessage sends message m to userID with one time notification token stored for tag.
+// Token is deleted from OTNStore after message is sent, since it can be used only once.
+// ErrUserOptedOut is returned for user in BlockList, token is kept
+func (msng *Messenger) SendStoredOTNMessage(ctx context.Context, userID, tag string, m Message) (FacebookResponse, error) {
+	if msng.OTNStore == nil {
+		return FacebookResponse{}, ErrNoOTNStore
+	}
+	// message is sent to token, not user ID, so send can't check the block list
+	if err := msng.checkUserBlocked(userID); err != nil {
+		return FacebookResponse{}, err
+	}
+	token, err := msng.OTNStore.GetToken(ctx, userID, tag)
+	if err != nil {
+		return FacebookResponse{}, fmt.Errorf("messenger: one time notification token for %s: %w", tag, err)
+	}
+
+	resp, err := msng.sendMessage(ctx, m, []SendOption{WithRecipient(OTNTokenRecipient{Token: token})})
+	if err != nil {
+		return resp, err
+	}
+	if err := msng.OTNStore.DeleteToken(ctx, userID, tag); err != nil {
+		msng.logger().Error("one time notification token delete failed", "user_id", userID, "error", err)
+	}
+	return resp, nil
+}
+
+// saveOTNToken saves one time notification token received in optin event
+func (msng *Messenger) saveOTNToken(ctx context.Context, userID int64, o FacebookOptin) {
+	if err := msng.OTNStore.SaveToken(ctx, strconv.FormatInt(userID, 10), o.OneTimeNotifToken, o.Payload); err != nil {
+		msng.logger().Error("one time notification token save failed", "user_id", userID, "error", err)
+	}
+}
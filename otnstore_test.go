@@ -0,0 +1,75 @@
+package messenger_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+const webhookOTNOptin = `{"object":"page","entry":[{"id":"1","time":1458692752478,"messaging":[{
+	"sender":{"id":"42"},"recipient":{"id":"1"},"timestamp":1458692752478,
+	"optin":{"type":"one_time_notif_req","payload":"PRICE_DROP","one_time_notif_token":"OTN_TOKEN"}}]}]}`
+
+func TestSendStoredOTNMessage(t *testing.T) {
+	fb.Reset()
+
+	ctx := context.Background()
+	store := messenger.NewMemoryOTNStore()
+	msng := messenger.New("XXXXXXX", "1", messenger.WithOTNStore(store))
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(webhookOTNOptin)))
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if token, err := store.GetToken(ctx, "42", "PRICE_DROP"); err == nil {
+			if token != "OTN_TOKEN" {
+				t.Fatal("Unexpected token", token)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Token not saved")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	m := msng.NewTextMessage(42, "Price dropped!")
+	if _, err := msng.SendStoredOTNMessage(ctx, "42", "PRICE_DROP", &m); err != nil {
+		t.Fatal(err)
+	}
+	call, _ := fb.LastCall()
+	if !strings.Contains(string(call.Body), `"recipient":{"one_time_notif_token":"OTN_TOKEN"}`) {
+		t.Error("Expected message sent with token, sent", string(call.Body))
+	}
+
+	if _, err := msng.SendStoredOTNMessage(ctx, "42", "PRICE_DROP", &m); !errors.Is(err, messenger.ErrNotFound) {
+		t.Error("Expected used token to be deleted, returned", err)
+	}
+}
+
+func TestSendStoredOTNMessageBlocked(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Message sent to blocked user")
+	})
+
+	ctx := context.Background()
+	store := messenger.NewMemoryOTNStore()
+	store.SaveToken(ctx, "42", "OTN_TOKEN", "PRICE_DROP")
+	blockList := messenger.NewMemoryBlockList()
+	blockList.Block("42")
+	msng := messenger.New("XXXXXXX", "1", mock, messenger.WithOTNStore(store), messenger.WithBlockList(blockList))
+
+	m := msng.NewTextMessage(42, "Price dropped!")
+	if _, err := msng.SendStoredOTNMessage(ctx, "42", "PRICE_DROP", &m); !errors.Is(err, messenger.ErrUserOptedOut) {
+		t.Error("Expected ErrUserOptedOut, returned", err)
+	}
+	if token, err := store.GetToken(ctx, "42", "PRICE_DROP"); err != nil || token != "OTN_TOKEN" {
+		t.Error("Expected token kept, returned", token, err)
+	}
+}
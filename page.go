@@ -0,0 +1,185 @@
+package messenger
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+)
+
+// cacheMu guards lazily cached Messenger fields
+var cacheMu sync.Mutex
+
+// PageInfo contains page and app linked with access token
+type PageInfo struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	AppID string `json:"app_id"`
+}
+
+// GetPageInfo returns page and app info for messenger's access token.
+// Call it once on startup, result is cached and used by methods like IsThreadOwner
+func (msng *Messenger) GetPageInfo(ctx context.Context) (PageInfo, error) {
+	var p PageInfo
+	q := url.Values{"fields": {"id,name"}}
+	if err := msng.graphRequest(ctx, http.MethodGet, "me", q, nil, &p); err != nil {
+		return PageInfo{}, err
+	}
+
+	var app struct {
+		ID string `json:"id"`
+	}
+	if err := msng.graphRequest(ctx, http.MethodGet, "app", url.Values{"fields": {"id"}}, nil, &app); err != nil {
+		return PageInfo{}, err
+	}
+	p.AppID = app.ID
+
+	cacheMu.Lock()
+	msng.pageInfo = &p
+	cacheMu.Unlock()
+	return p, nil
+}
+
+// cachedPageInfo returns page info from cache, or calls GetPageInfo if not cached yet
+func (msng *Messenger) cachedPageInfo(ctx context.Context) (PageInfo, error) {
+	cacheMu.Lock()
+	p := msng.pageInfo
+	cacheMu.Unlock()
+	if p != nil {
+		return *p, nil
+	}
+	return msng.GetPageInfo(ctx)
+}
+
+// BotInfo describes page the bot is running for
+type BotInfo struct {
+	ID             string
+	Name           string
+	Category       string
+	Link           string
+	About          string
+	Description    string
+	ProfilePicture string // URL of page profile picture
+	CoverPhoto     string // URL of page cover photo
+}
+
+// botInfoCache holds bot info shared by messenger and its copies given to event handlers
+type botInfoCache struct {
+	mu   sync.Mutex // held while fetching, so concurrent first callers wait for one request
+	info atomic.Pointer[BotInfo]
+}
+
+// botInfoCache returns shared bot info cache, it is created here if messenger was not created with New
+func (msng *Messenger) botInfoCache() *botInfoCache {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if msng.botInfo == nil {
+		msng.botInfo = &botInfoCache{}
+	}
+	return msng.botInfo
+}
+
+// GetBotInfo returns info about messenger's page. It is fetched once, later calls return cached info.
+// Failed fetch is not cached, next call tries again
+func (msng *Messenger) GetBotInfo(ctx context.Context) (BotInfo, error) {
+	c := msng.botInfoCache()
+	if info := c.info.Load(); info != nil {
+		return *info, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if info := c.info.Load(); info != nil {
+		return *info, nil
+	}
+
+	var raw struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Category    string `json:"category"`
+		Link        string `json:"link"`
+		About       string `json:"about"`
+		Description string `json:"description"`
+		Picture     struct {
+			Data struct {
+				URL string `json:"url"`
+			} `json:"data"`
+		} `json:"picture"`
+		Cover struct {
+			Source string `json:"source"`
+		} `json:"cover"`
+	}
+	q := url.Values{"fields": {"id,name,category,link,about,description,picture,cover"}}
+	if err := msng.graphRequest(ctx, http.MethodGet, "me", q, nil, &raw); err != nil {
+		return BotInfo{}, err
+	}
+	info := BotInfo{
+		ID:             raw.ID,
+		Name:           raw.Name,
+		Category:       raw.Category,
+		Link:           raw.Link,
+		About:          raw.About,
+		Description:    raw.Description,
+		ProfilePicture: raw.Picture.Data.URL,
+		CoverPhoto:     raw.Cover.Source,
+	}
+
+	c.info.Store(&info)
+	return info, nil
+}
+
+// PageName returns name of messenger's page cached by GetBotInfo or GetPageInfo, empty if neither was called
+func (msng *Messenger) PageName() string {
+	if info := msng.botInfoCache().info.Load(); info != nil {
+		return info.Name
+	}
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if msng.pageInfo != nil {
+		return msng.pageInfo.Name
+	}
+	return ""
+}
+
+// GetPageID returns configured page ID. It is named like GetClient, since method can't be named
+// the same as PageID field
+func (msng *Messenger) GetPageID() string {
+	return msng.PageID
+}
+
+// PageAccount is page managed by user, with page access token
+type PageAccount struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	AccessToken string   `json:"access_token"`
+	Category    string   `json:"category"`
+	Tasks       []string `json:"tasks"`
+}
+
+// GetManagedPages returns all pages managed by user with user access token userToken
+func (msng *Messenger) GetManagedPages(ctx context.Context, userToken string) ([]PageAccount, error) {
+	return FetchAll(ctx, func(after string) (PagedResponse[PageAccount], error) {
+		q := url.Values{}
+		if after != "" {
+			q.Set("after", after)
+		}
+		var page PagedResponse[PageAccount]
+		err := doGraphRequest(ctx, msng.GetClient(), http.MethodGet, msng.graphURL("me/accounts", q, userToken), nil, &page)
+		return page, err
+	}, 0)
+}
+
+// GetPageAccessToken exchanges user access token for page access token of page with pageID.
+// ErrNotFound is returned if user doesn't manage the page
+func (msng *Messenger) GetPageAccessToken(ctx context.Context, userToken string, pageID string) (string, error) {
+	pages, err := msng.GetManagedPages(ctx, userToken)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range pages {
+		if p.ID == pageID {
+			return p.AccessToken, nil
+		}
+	}
+	return "", ErrNotFound
+}
@@ -0,0 +1,117 @@
+package messenger_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestGetPageAccessToken(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("access_token") != "USER_TOKEN" {
+			t.Error("Expected user token, sent", r.FormValue("access_token"))
+		}
+		if r.FormValue("after") == "" {
+			w.Write([]byte(`{"data":[{"id":"1","name":"First","access_token":"PAGE1"}],"paging":{"cursors":{"after":"A"},"next":"http://` + r.Host + `/me/accounts?access_token=USER_TOKEN&after=A"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"2","name":"Second","access_token":"PAGE2","tasks":["MESSAGING"]}]}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	token, err := msng.GetPageAccessToken(context.Background(), "USER_TOKEN", "2")
+	if err != nil || token != "PAGE2" {
+		t.Error("Expected PAGE2, returned", token, err)
+	}
+
+	if _, err := msng.GetPageAccessToken(context.Background(), "USER_TOKEN", "3"); err != messenger.ErrNotFound {
+		t.Error("Expected ErrNotFound, returned", err)
+	}
+}
+
+func TestGetBotInfo(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/me" {
+			t.Error("Unexpected path", r.URL.Path)
+		}
+		w.Write([]byte(`{"id":"1","name":"Pizza Bot","category":"Restaurant","link":"https://www.facebook.com/pizzabot",
+			"picture":{"data":{"url":"https://example.com/pic.jpg"}},"cover":{"source":"https://example.com/cover.jpg"}}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "1", mock)
+	if name := msng.PageName(); name != "" {
+		t.Error("Expected no page name before fetching, returned", name)
+	}
+	for i := 0; i < 2; i++ {
+		info, err := msng.GetBotInfo(context.Background())
+		if err != nil || info.Name != "Pizza Bot" || info.ProfilePicture != "https://example.com/pic.jpg" || info.CoverPhoto != "https://example.com/cover.jpg" {
+			t.Fatal("Unexpected bot info", info, err)
+		}
+	}
+	if calls != 1 {
+		t.Error("Expected bot info fetched once, fetched", calls)
+	}
+	if name := msng.PageName(); name != "Pizza Bot" {
+		t.Error("Expected Pizza Bot, returned", name)
+	}
+	if id := msng.GetPageID(); id != "1" {
+		t.Error("Expected configured page ID, returned", id)
+	}
+}
+
+func TestGetBotInfoShared(t *testing.T) {
+	t.Parallel()
+	var calls int32
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{"id":"1","name":"Pizza Bot"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "1", mock)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := msng.GetBotInfo(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Error("Expected bot info fetched once by concurrent callers, fetched", calls)
+	}
+
+	// handlers get copy of messenger, info fetched there is seen by the original
+	msng2 := messenger.New("XXXXXXX", "1", mock)
+	done := make(chan struct{})
+	var once sync.Once
+	msng2.MessageReceived = func(hm *messenger.Messenger, userID int64, m messenger.FacebookMessage) {
+		hm.GetBotInfo(context.Background())
+		once.Do(func() { close(done) })
+	}
+	msng2.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage)))
+	<-done
+	if name := msng2.PageName(); name != "Pizza Bot" {
+		t.Error("Expected bot info fetched in handler to be cached, page name", name)
+	}
+
+	// clone for other page doesn't share the cache
+	clone := msng2.Clone(messenger.WithPageID("2"))
+	if name := clone.PageName(); name != "" {
+		t.Error("Expected no page name for other page, returned", name)
+	}
+}
@@ -0,0 +1,173 @@
+package messenger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// DefaultMaxPages is maximum number of pages CollectAll fetches if PageIterator.MaxPages is not set.
+// It guards against infinite loops if API keeps returning next cursor
+const DefaultMaxPages = 1000
+
+// PageIterator iterates cursor paginated Graph API results
+type PageIterator[T any] struct {
+	// MaxPages limits number of pages fetched by CollectAll, DefaultMaxPages if 0
+	MaxPages int
+
+	fetcher func(ctx context.Context, cursor string) ([]T, string, error)
+	cursor  string
+	done    bool
+}
+
+// NewPageIterator creates iterator, fetcher is called with cursor of the page ("" for the first page)
+// and returns page items and cursor of the next page ("" if there are no more pages)
+func NewPageIterator[T any](fetcher func(ctx context.Context, cursor string) ([]T, string, error)) *PageIterator[T] {
+	return &PageIterator[T]{fetcher: fetcher}
+}
+
+// HasNext reports whether there are more pages to fetch
+func (it *PageIterator[T]) HasNext() bool {
+	return !it.done
+}
+
+// Next fetches next page
+func (it *PageIterator[T]) Next(ctx context.Context) ([]T, error) {
+	if it.done {
+		return nil, ErrNoMorePages
+	}
+
+	items, next, err := it.fetcher(ctx, it.cursor)
+	if err != nil {
+		return nil, err
+	}
+	it.cursor = next
+	it.done = next == ""
+	return items, nil
+}
+
+// CollectAll fetches all remaining pages and returns their items
+func CollectAll[T any](ctx context.Context, iter *PageIterator[T]) ([]T, error) {
+	maxPages := iter.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+
+	var all []T
+	for pages := 0; iter.HasNext(); pages++ {
+		if pages == maxPages {
+			return all, fmt.Errorf("%w: more than %d", ErrTooManyPages, maxPages)
+		}
+		items, err := iter.Next(ctx)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// Paged is implemented by paginated Graph API responses
+type Paged[T any] interface {
+	Items() []T
+	NextCursor() string // "" for the last page
+}
+
+// PagedResponse is paging envelope of Graph API list responses
+type PagedResponse[T any] struct {
+	Data   []T    `json:"data"`
+	Paging Paging `json:"paging"`
+}
+
+// Paging of Graph API list response
+type Paging struct {
+	Cursors  Cursors `json:"cursors"`
+	Next     string  `json:"next"`     // URL of the next page, empty for the last page
+	Previous string  `json:"previous"` // URL of the previous page, empty for the first page
+}
+
+// Cursors point to the first and the last item of the page
+type Cursors struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Items returns page items
+func (p PagedResponse[T]) Items() []T {
+	return p.Data
+}
+
+// NextCursor returns cursor of the next page, or "" if this is the last page
+func (p PagedResponse[T]) NextCursor() string {
+	if p.Paging.Next == "" {
+		return ""
+	}
+	return p.Paging.Cursors.After
+}
+
+// FetchAll calls fetcher with cursor of each page ("" for the first page) until all pages are fetched
+// and returns their items. ErrTooManyPages is returned with items fetched so far if there are more than maxPages pages,
+// DefaultMaxPages is used if maxPages is 0
+func FetchAll[T any](ctx context.Context, fetcher func(after string) (PagedResponse[T], error), maxPages int) ([]T, error) {
+	return fetchAllPaged[T](ctx, fetcher, maxPages)
+}
+
+// fetchAllPaged is FetchAll for any paginated response implementing Paged
+func fetchAllPaged[T any, P Paged[T]](ctx context.Context, fetcher func(after string) (P, error), maxPages int) ([]T, error) {
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+
+	var all []T
+	after := ""
+	for pages := 0; ; pages++ {
+		if pages == maxPages {
+			return all, fmt.Errorf("%w: more than %d", ErrTooManyPages, maxPages)
+		}
+		if err := ctx.Err(); err != nil {
+			return all, err
+		}
+		page, err := fetcher(after)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, page.Items()...)
+		if after = page.NextCursor(); after == "" {
+			return all, nil
+		}
+	}
+}
+
+// fetchGraphPage fetches page of Graph API list at path
+func fetchGraphPage[T any](ctx context.Context, msng *Messenger, path string, query url.Values, after string) (PagedResponse[T], error) {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	if after != "" {
+		q.Set("after", after)
+	}
+
+	var page PagedResponse[T]
+	err := msng.graphRequest(ctx, http.MethodGet, path, q, nil, &page)
+	return page, err
+}
+
+// graphPageFetcher returns page fetcher for Graph API list at path, use it with NewPageIterator
+func graphPageFetcher[T any](msng *Messenger, path string, query url.Values) func(ctx context.Context, cursor string) ([]T, string, error) {
+	return func(ctx context.Context, cursor string) ([]T, string, error) {
+		page, err := fetchGraphPage[T](ctx, msng, path, query, cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Items(), page.NextCursor(), nil
+	}
+}
+
+// fetchAllGraphPages fetches all pages of Graph API list at path
+func fetchAllGraphPages[T any](ctx context.Context, msng *Messenger, path string, query url.Values) ([]T, error) {
+	return FetchAll(ctx, func(after string) (PagedResponse[T], error) {
+		return fetchGraphPage[T](ctx, msng, path, query, after)
+	}, 0)
+}
@@ -0,0 +1,76 @@
+package messenger_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestPageIterator(t *testing.T) {
+	pages := map[string][]int{"": {1, 2}, "b": {3, 4}, "c": {5}}
+	next := map[string]string{"": "b", "b": "c", "c": ""}
+
+	it := messenger.NewPageIterator(func(ctx context.Context, cursor string) ([]int, string, error) {
+		return pages[cursor], next[cursor], nil
+	})
+	all, err := messenger.CollectAll(context.Background(), it)
+	if err != nil || len(all) != 5 || all[4] != 5 {
+		t.Error("Unexpected items", all, err)
+	}
+	if it.HasNext() {
+		t.Error("Iterator should be done")
+	}
+
+	endless := messenger.NewPageIterator(func(ctx context.Context, cursor string) ([]int, string, error) {
+		return []int{1}, "next", nil
+	})
+	endless.MaxPages = 3
+	if all, err := messenger.CollectAll(context.Background(), endless); err == nil || len(all) != 3 {
+		t.Error("Expected max pages error, returned", len(all), err)
+	}
+}
+
+func TestFetchAll(t *testing.T) {
+	pages := map[string]messenger.PagedResponse[int]{
+		"":  {Data: []int{1, 2}, Paging: messenger.Paging{Cursors: messenger.Cursors{After: "b"}, Next: "https://graph.facebook.com/next"}},
+		"b": {Data: []int{3}, Paging: messenger.Paging{Cursors: messenger.Cursors{After: "c"}}},
+	}
+	all, err := messenger.FetchAll(context.Background(), func(after string) (messenger.PagedResponse[int], error) {
+		return pages[after], nil
+	}, 0)
+	if err != nil || len(all) != 3 || all[2] != 3 {
+		t.Error("Unexpected items", all, err)
+	}
+
+	endless := func(after string) (messenger.PagedResponse[int], error) {
+		return messenger.PagedResponse[int]{Data: []int{1}, Paging: messenger.Paging{Cursors: messenger.Cursors{After: "x"}, Next: "next"}}, nil
+	}
+	if all, err := messenger.FetchAll(context.Background(), endless, 2); !errors.Is(err, messenger.ErrTooManyPages) || len(all) != 2 {
+		t.Error("Expected ErrTooManyPages, returned", len(all), err)
+	}
+}
+
+func TestGetPSIDsForLabel(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("after") == "" {
+			w.Write([]byte(`{"data":[{"id":"1"},{"id":"2"}],"paging":{"cursors":{"after":"AFTER"},"next":"https://graph.facebook.com/next"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"3"}],"paging":{"cursors":{"before":"BEFORE"}}}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	psids, err := msng.GetPSIDsForLabel(context.Background(), "42")
+	if err != nil || len(psids) != 3 || psids[2] != "3" {
+		t.Error("Unexpected PSIDs", psids, err)
+	}
+
+	psids, err = messenger.CollectAll(context.Background(), msng.IteratePSIDsForLabel("42"))
+	if err != nil || len(psids) != 3 || psids[2] != "3" {
+		t.Error("Unexpected PSIDs", psids, err)
+	}
+}
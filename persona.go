@@ -0,0 +1,66 @@
+package messenger
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Persona is identity (name and profile picture) that messages are sent as, e.g. for human agents
+type Persona struct {
+	ID                string `json:"id,omitempty"`
+	Name              string `json:"name"`
+	ProfilePictureURL string `json:"profile_picture_url"`
+}
+
+// CreatePersona creates persona and returns its ID
+func (msng *Messenger) CreatePersona(ctx context.Context, name, profilePictureURL string) (string, error) {
+	var reply struct {
+		ID string `json:"id"`
+	}
+	p := Persona{Name: name, ProfilePictureURL: profilePictureURL}
+	if err := msng.graphRequest(ctx, http.MethodPost, "me/personas", nil, p, &reply); err != nil {
+		return "", err
+	}
+	return reply.ID, nil
+}
+
+// GetPersona returns persona with personaID
+func (msng *Messenger) GetPersona(ctx context.Context, personaID string) (Persona, error) {
+	var p Persona
+	err := msng.graphRequest(ctx, http.MethodGet, url.PathEscape(personaID), nil, nil, &p)
+	return p, err
+}
+
+// DeletePersona deletes persona with personaID
+func (msng *Messenger) DeletePersona(ctx context.Context, personaID string) error {
+	return msng.graphRequest(ctx, http.MethodDelete, url.PathEscape(personaID), nil, nil, nil)
+}
+
+// WithDefaultPersona sends all messages as persona with personaID, unless other persona is set with WithPersona
+func WithDefaultPersona(personaID string) Option {
+	return func(msng *Messenger) {
+		msng.DefaultPersonaID = personaID
+	}
+}
+
+// ClearDefaultPersona stops sending messages as default persona
+func (msng *Messenger) ClearDefaultPersona() {
+	msng.DefaultPersonaID = ""
+}
+
+// WithPersona sends message as persona with personaID
+func WithPersona(personaID string) SendOption {
+	return func(o *sendOptions) {
+		o.set("persona_id", personaID)
+	}
+}
+
+// SendTextMessageAsPersona sends text message to recipientID as persona with personaID
+func (msng *Messenger) SendTextMessageAsPersona(ctx context.Context, recipientID, personaID, text string) (FacebookResponse, error) {
+	m := TextMessage{
+		Recipient: newRecipient(recipientID),
+		Message:   textMessageContent{Text: text},
+	}
+	return msng.sendMessage(ctx, &m, []SendOption{WithPersona(personaID)})
+}
@@ -0,0 +1,52 @@
+package messenger_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestDefaultPersona(t *testing.T) {
+	t.Parallel()
+	var body string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		if r.URL.Path == "/me/personas" {
+			w.Write([]byte(`{"id":"ARIA"}`))
+			return
+		}
+		w.Write([]byte(`{"recipient_id":"12123213123","message_id":"mid.1"}`))
+	})
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "", mock)
+	id, err := msng.CreatePersona(ctx, "Aria from Support", "https://example.com/aria.jpg")
+	if err != nil || id != "ARIA" {
+		t.Fatal("Unexpected persona", id, err)
+	}
+	if body != `{"name":"Aria from Support","profile_picture_url":"https://example.com/aria.jpg"}` {
+		t.Error("Unexpected create persona request", body)
+	}
+
+	msng = messenger.New("XXXXXXX", "", mock, messenger.WithDefaultPersona(id))
+	msng.SendTextMessageStr(ctx, "12123213123", "hello")
+	if !strings.Contains(body, `"persona_id":"ARIA"`) {
+		t.Error("Expected default persona, sent", body)
+	}
+
+	msng.SendTextMessageAsPersona(ctx, "12123213123", "BOB", "hello")
+	if !strings.Contains(body, `"persona_id":"BOB"`) {
+		t.Error("Expected persona BOB, sent", body)
+	}
+
+	msng.ClearDefaultPersona()
+	msng.SendTextMessageStr(ctx, "12123213123", "hello")
+	if strings.Contains(body, "persona_id") {
+		t.Error("Expected no persona, sent", body)
+	}
+}
@@ -0,0 +1,49 @@
+package messenger
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ValidateMessagePolicy checks if combination of messaging type, tag and notification type
+// of message m complies with Messenger Platform policy. SendMessage calls it before sending
+func ValidateMessagePolicy(m Message) error {
+	s, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return validatePolicy(s)
+}
+
+// validatePolicy validates JSON encoded message
+func validatePolicy(s []byte) error {
+	var f struct {
+		MessagingType    MessagingType    `json:"messaging_type"`
+		Tag              MessageTag       `json:"tag"`
+		NotificationType NotificationType `json:"notification_type"`
+	}
+	if err := json.Unmarshal(s, &f); err != nil {
+		return err
+	}
+
+	switch f.NotificationType {
+	case "", NotificationTypeRegular, NotificationTypeSilentPush, NotificationTypeNoPush:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidNotificationType, f.NotificationType)
+	}
+
+	switch f.MessagingType {
+	case "", MessagingTypeResponse, MessagingTypeUpdate, MessagingTypeSubscription:
+		if f.Tag != "" {
+			return fmt.Errorf("%w: messaging type is %q", ErrTagRequiresMessageTag, f.MessagingType)
+		}
+	case MessagingTypeMessageTag:
+		if f.Tag == "" {
+			return ErrMessageTagMissing
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidMessagingType, f.MessagingType)
+	}
+
+	return nil
+}
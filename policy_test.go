@@ -0,0 +1,114 @@
+package messenger_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestValidateMessagePolicy(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+
+	tm := msng.NewTextMessage(12123213123, "hello")
+	if err := messenger.ValidateMessagePolicy(tm); err != nil {
+		t.Error("Plain text message should be valid", err)
+	}
+
+	tm.Tag = messenger.MessageTagAccountUpdate
+	if err := messenger.ValidateMessagePolicy(tm); !errors.Is(err, messenger.ErrTagRequiresMessageTag) {
+		t.Error("Expected ErrTagRequiresMessageTag, returned", err)
+	}
+
+	tm.MessagingType = messenger.MessagingTypeMessageTag
+	if err := messenger.ValidateMessagePolicy(tm); err != nil {
+		t.Error("Tagged message should be valid", err)
+	}
+
+	tm.Tag = ""
+	if err := messenger.ValidateMessagePolicy(tm); !errors.Is(err, messenger.ErrMessageTagMissing) {
+		t.Error("Expected ErrMessageTagMissing, returned", err)
+	}
+
+	gm := msng.NewGenericMessage(12123213123)
+	gm.MessagingType = messenger.MessagingTypeMessageTag
+	gm.Tag = messenger.MessageTagHumanAgent
+	if err := messenger.ValidateMessagePolicy(gm); err != nil {
+		t.Error("Human agent message with attachment should be valid", err)
+	}
+}
+
+func TestWithSubscriptionCategory(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+	tm := msng.NewTextMessage(12123213123, "news")
+
+	if _, err := msng.SendMessage(tm, messenger.WithSubscriptionCategory(messenger.SubscriptionCategoryNews)); err != nil {
+		t.Error("Send failed", err)
+	}
+
+	_, err := msng.SendMessage(tm, messenger.WithSubscriptionCategory("GAMES"))
+	if !errors.Is(err, messenger.ErrInvalidSubscriptionCategory) {
+		t.Error("Expected ErrInvalidSubscriptionCategory, returned", err)
+	}
+}
+
+func TestWithMetadata(t *testing.T) {
+	t.Parallel()
+	var body string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"recipient_id":"12123213123","message_id":"mid.1"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	tm := msng.NewTextMessage(12123213123, "hello")
+	if _, err := msng.SendMessage(tm, messenger.WithMetadata("order-42")); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, `"message":{"metadata":"order-42","text":"hello"}`) {
+		t.Error("Metadata not sent", body)
+	}
+
+	tm.Message.Metadata = strings.Repeat("x", 1001)
+	if _, err := msng.SendMessage(tm); !errors.Is(err, messenger.ErrMetadataTooLong) {
+		t.Error("Expected ErrMetadataTooLong, returned", err)
+	}
+}
+
+func TestSendWithTag(t *testing.T) {
+	t.Parallel()
+	var body string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"recipient_id":"12123213123","message_id":"mid.1"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	gm := msng.NewGenericMessage(0)
+	gm.AddNewElement("Your order shipped", "", "", "", nil)
+	if _, err := msng.SendWithTag(context.Background(), "12123213123", gm, messenger.MessageTagPostPurchaseUpdate); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`"messaging_type":"MESSAGE_TAG"`, `"tag":"POST_PURCHASE_UPDATE"`, `"recipient":{"id":"12123213123"}`} {
+		if !strings.Contains(body, s) {
+			t.Error("Expected", s, "sent", body)
+		}
+	}
+
+	if _, err := msng.SendAsHumanAgent(context.Background(), "12123213123", "Hi, this is Ana"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, `"tag":"HUMAN_AGENT"`) || !strings.Contains(body, `"text":"Hi, this is Ana"`) {
+		t.Error("Unexpected human agent message", body)
+	}
+
+	if _, err := msng.SendWithTag(context.Background(), "12123213123", gm, messenger.MessageTagHumanAgent); err != nil {
+		t.Error("Human agent message with attachment should be sent", err)
+	}
+}
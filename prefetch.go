@@ -0,0 +1,71 @@
+package messenger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// AttachmentMeta is metadata of attachment URL returned by PrefetchAttachment
+type AttachmentMeta struct {
+	ContentType   string
+	ContentLength int64 // -1 if unknown
+	StatusCode    int
+}
+
+// attachmentMediaTypes are content types Facebook accepts for attachments sent by URL
+var attachmentMediaTypes = []string{"image/", "video/", "audio/", "application/"}
+
+// PrefetchAttachment requests headers of attachment url without downloading it.
+// Servers that don't support HEAD requests are asked with GET and response body is discarded
+func (msng *Messenger) PrefetchAttachment(ctx context.Context, url string) (AttachmentMeta, error) {
+	resp, err := msng.fetchHeaders(ctx, http.MethodHead, url)
+	if err == nil && resp.StatusCode == http.StatusMethodNotAllowed {
+		resp, err = msng.fetchHeaders(ctx, http.MethodGet, url)
+	}
+	if err != nil {
+		return AttachmentMeta{}, err
+	}
+	return AttachmentMeta{
+		ContentType:   resp.Header.Get("Content-Type"),
+		ContentLength: resp.ContentLength,
+		StatusCode:    resp.StatusCode,
+	}, nil
+}
+
+func (msng *Messenger) fetchHeaders(ctx context.Context, method, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := msng.GetClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096)) // let small bodies reuse connection
+	resp.Body.Close()
+	return resp, nil
+}
+
+// ValidateAttachmentURL checks that attachment url is accessible and returns media content,
+// ErrInvalidAttachmentURL is returned if status is not 200 OK or content type is not image, video, audio or application
+func (msng *Messenger) ValidateAttachmentURL(ctx context.Context, url string) error {
+	meta, err := msng.PrefetchAttachment(ctx, url)
+	if err != nil {
+		return err
+	}
+	if meta.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s returned status %d", ErrInvalidAttachmentURL, url, meta.StatusCode)
+	}
+	mediaType, _, _ := mime.ParseMediaType(meta.ContentType)
+	for _, prefix := range attachmentMediaTypes {
+		if strings.HasPrefix(mediaType, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s has content type %q", ErrInvalidAttachmentURL, url, meta.ContentType)
+}
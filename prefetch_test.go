@@ -0,0 +1,54 @@
+package messenger_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestValidateAttachmentURL(t *testing.T) {
+	t.Parallel()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image.png":
+			if r.Method != http.MethodHead {
+				t.Error("Expected HEAD request, sent", r.Method)
+			}
+			w.Header().Set("Content-Type", "image/png")
+			w.Header().Set("Content-Length", "1024")
+		case "/nohead.mp4":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.Header().Set("Content-Type", "video/mp4")
+			w.Write([]byte("video"))
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "")
+	meta, err := msng.PrefetchAttachment(ctx, s.URL+"/image.png")
+	if err != nil || meta != (messenger.AttachmentMeta{ContentType: "image/png", ContentLength: 1024, StatusCode: http.StatusOK}) {
+		t.Error("Unexpected meta", meta, err)
+	}
+
+	for path, valid := range map[string]bool{"/image.png": true, "/nohead.mp4": true, "/page.html": false, "/missing.png": false} {
+		err := msng.ValidateAttachmentURL(ctx, s.URL+path)
+		if valid && err != nil {
+			t.Error("Expected", path, "to be valid, returned", err)
+		}
+		if !valid && !errors.Is(err, messenger.ErrInvalidAttachmentURL) {
+			t.Error("Expected ErrInvalidAttachmentURL for", path, "returned", err)
+		}
+	}
+}
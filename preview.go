@@ -0,0 +1,93 @@
+package messenger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RenderMessagePreview returns plain text preview of message m showing text, cards and buttons,
+// used for checking template messages in logs and tests
+func RenderMessagePreview(m Message) (string, error) {
+	switch m := m.(type) {
+	case TextMessage:
+		return m.Message.Text, nil
+	case *TextMessage:
+		return m.Message.Text, nil
+	case GenericMessage:
+		return renderPayload(m.Message.Attachment), nil
+	case *GenericMessage:
+		return renderPayload(m.Message.Attachment), nil
+	case ButtonMessage:
+		return renderPayload(m.Message.Attachment), nil
+	case *ButtonMessage:
+		return renderPayload(m.Message.Attachment), nil
+	case OneTimeNotifRequestMessage:
+		return renderPayload(m.Message.Attachment), nil
+	case *OneTimeNotifRequestMessage:
+		return renderPayload(m.Message.Attachment), nil
+	case interface{ renderPreview() string }: // TemplateMessage
+		return m.renderPreview(), nil
+	}
+	return "", fmt.Errorf("%w: %T", ErrUnknownMessageType, m)
+}
+
+func renderPayload(a *attachment) string {
+	if a == nil {
+		return ""
+	}
+	p := a.Payload
+
+	var lines []string
+	switch TemplateType(p.TemplateType) {
+	case TemplateTypeGeneric:
+		for i, e := range p.Elements {
+			lines = append(lines, e.previewLines(i+1)...)
+		}
+	case TemplateTypeButton:
+		lines = append(lines, p.Text, renderButtons(p.Buttons))
+	case TemplateTypeOneTimeNotifReq:
+		lines = append(lines, p.Title, "[Notify Me]")
+	default:
+		lines = append(lines, "Template: "+p.TemplateType)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// elementPreview returns preview lines of template element with title line, subtitle and buttons
+func elementPreview(title, subtitle string, buttons []Button) []string {
+	lines := []string{title}
+	if subtitle != "" {
+		lines = append(lines, "  "+subtitle)
+	}
+	if len(buttons) > 0 {
+		lines = append(lines, "  "+renderButtons(buttons))
+	}
+	return lines
+}
+
+func renderButtons(buttons []Button) string {
+	labels := make([]string, len(buttons))
+	for i, b := range buttons {
+		labels[i] = "[" + b.Title + "]"
+	}
+	return strings.Join(labels, " ")
+}
+
+// EqualMessages reports if messages a and b are sent as the same JSON, used for assertions in tests
+func EqualMessages(a, b Message) bool {
+	var va, vb interface{}
+	if !decodeMessage(a, &va) || !decodeMessage(b, &vb) {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func decodeMessage(m Message, v interface{}) bool {
+	s, err := json.Marshal(m)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(s, v) == nil
+}
@@ -0,0 +1,46 @@
+package messenger_test
+
+import (
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestRenderMessagePreview(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+
+	text := msng.NewTextMessage(1, "Hello")
+	gm := msng.NewGenericMessage(1)
+	gm.AddNewElement("Shoes", "Red, size 42", "", "", []messenger.Button{msng.NewPostbackButton("Buy", "BUY"), msng.NewWebURLButton("Details", "https://example.com")})
+	gm.AddNewElement("Hat", "", "", "", nil)
+	bm := msng.NewButtonMessage(1, "Pick one", []messenger.Button{msng.NewPostbackButton("Yes", "Y"), msng.NewPostbackButton("No", "N")})
+
+	tests := []struct {
+		m    messenger.Message
+		want string
+	}{
+		{&text, "Hello"},
+		{gm, "Card 1: Shoes\n  Red, size 42\n  [Buy] [Details]\nCard 2: Hat"},
+		{&bm, "Pick one\n[Yes] [No]"},
+	}
+	for _, test := range tests {
+		got, err := messenger.RenderMessagePreview(test.m)
+		if err != nil || got != test.want {
+			t.Errorf("Expected preview %q, got %q %v", test.want, got, err)
+		}
+	}
+}
+
+func TestEqualMessages(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+	a := msng.NewTextMessage(1, "Hello")
+	b := msng.NewTextMessage(1, "Hello")
+	c := msng.NewTextMessage(2, "Hello")
+
+	if !messenger.EqualMessages(&a, b) {
+		t.Error("Expected equal messages")
+	}
+	if messenger.EqualMessages(a, c) {
+		t.Error("Expected different messages")
+	}
+}
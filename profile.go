@@ -0,0 +1,243 @@
+package messenger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const messengerProfilePath = "me/messenger_profile"
+
+// localeRe matches Facebook locale codes like en_US
+var localeRe = regexp.MustCompile(`^[a-z]{2}_[A-Z]{2}$`)
+
+// SupportedLocales lists all locales documented on Messenger Platform. Locales are validated by format
+// with ValidateLocale, not against this list, so locales Facebook adds later can be used too
+var SupportedLocales = []string{
+	"af_ZA", "ar_AR", "as_IN", "az_AZ", "be_BY", "bg_BG", "bn_IN", "br_FR", "bs_BA", "ca_ES",
+	"cb_IQ", "co_FR", "cs_CZ", "cx_PH", "cy_GB", "da_DK", "de_DE", "el_GR", "en_GB", "en_UD",
+	"en_US", "es_ES", "es_LA", "et_EE", "eu_ES", "fa_IR", "ff_NG", "fi_FI", "fo_FO", "fr_CA",
+	"fr_FR", "fy_NL", "ga_IE", "gl_ES", "gn_PY", "gu_IN", "ha_NG", "he_IL", "hi_IN", "hr_HR",
+	"hu_HU", "hy_AM", "id_ID", "is_IS", "it_IT", "ja_JP", "ja_KS", "jv_ID", "ka_GE", "kk_KZ",
+	"km_KH", "kn_IN", "ko_KR", "ku_TR", "lt_LT", "lv_LV", "mg_MG", "mk_MK", "ml_IN", "mn_MN",
+	"mr_IN", "ms_MY", "mt_MT", "my_MM", "nb_NO", "ne_NP", "nl_BE", "nl_NL", "nn_NO", "or_IN",
+	"pa_IN", "pl_PL", "ps_AF", "pt_BR", "pt_PT", "qz_MM", "ro_RO", "ru_RU", "rw_RW", "sc_IT",
+	"si_LK", "sk_SK", "sl_SI", "so_SO", "sq_AL", "sr_RS", "sv_SE", "sw_KE", "sz_PL", "ta_IN",
+	"te_IN", "tg_TJ", "th_TH", "tl_PH", "tr_TR", "tz_MA", "uk_UA", "ur_PK", "uz_UZ", "vi_VN",
+	"zh_CN", "zh_HK", "zh_TW",
+}
+
+// ValidateLocale reports if locale is "default" or locale code in language_COUNTRY format, like en_US
+func ValidateLocale(locale string) bool {
+	return locale == string(LocaleMenuDefault) || localeRe.MatchString(locale)
+}
+
+// MustBeValidLocale panics if locale is not valid, see ValidateLocale. Use it for locales set in init functions
+func MustBeValidLocale(locale string) {
+	if !ValidateLocale(locale) {
+		panic(fmt.Sprintf("messenger: invalid locale %q, expected \"default\" or locale like en_US", locale))
+	}
+}
+
+// SetSupportedLocales sets languages supported by your bot, locales are in format en_US
+func (msng *Messenger) SetSupportedLocales(ctx context.Context, locales []string) error {
+	for _, l := range locales {
+		if !localeRe.MatchString(l) {
+			return fmt.Errorf("%w: %q", ErrInvalidLocale, l)
+		}
+	}
+	return msng.setMessengerProfile(ctx, map[string]interface{}{"supported_locales": locales})
+}
+
+// GetSupportedLocales returns languages supported by your bot
+func (msng *Messenger) GetSupportedLocales(ctx context.Context) ([]string, error) {
+	var p struct {
+		SupportedLocales []string `json:"supported_locales"`
+	}
+	err := msng.getMessengerProfile(ctx, []string{"supported_locales"}, &p)
+	return p.SupportedLocales, err
+}
+
+// DeleteSupportedLocales removes supported languages from messenger profile
+func (msng *Messenger) DeleteSupportedLocales(ctx context.Context) error {
+	return msng.DeleteMessengerProfileFields(ctx, []string{"supported_locales"})
+}
+
+// MessengerProfileFields lists all messenger profile fields, GetMessengerProfile requests them if no fields are given
+var MessengerProfileFields = []string{
+	"greeting", "get_started", "persistent_menu", "whitelisted_domains", "account_linking_url",
+	"payment_settings", "target_audience", "home_url", "ice_breakers", "supported_locales",
+}
+
+// MessengerProfile is page's messenger profile, fields that are not set or requested are empty
+type MessengerProfile struct {
+	Greeting           []Greeting       `json:"greeting,omitempty"`
+	GetStarted         *GetStarted      `json:"get_started,omitempty"`
+	PersistentMenu     []PersistentMenu `json:"persistent_menu,omitempty"`
+	WhitelistedDomains []string         `json:"whitelisted_domains,omitempty"`
+	AccountLinkingURL  string           `json:"account_linking_url,omitempty"`
+	PaymentSettings    json.RawMessage  `json:"payment_settings,omitempty"`
+	TargetAudience     json.RawMessage  `json:"target_audience,omitempty"`
+	HomeURL            json.RawMessage  `json:"home_url,omitempty"`
+	IceBreakers        json.RawMessage  `json:"ice_breakers,omitempty"`
+	SupportedLocales   []string         `json:"supported_locales,omitempty"`
+}
+
+// Greeting is greeting text for one locale, "default" or locale like en_US
+type Greeting struct {
+	Locale string `json:"locale"`
+	Text   string `json:"text"`
+}
+
+// GreetingTemplate is placeholder in greeting text that Facebook replaces with user's name
+type GreetingTemplate string
+
+const (
+	// GreetingFirstName is replaced with user's first name
+	GreetingFirstName = GreetingTemplate("{{user_first_name}}")
+
+	// GreetingLastName is replaced with user's last name
+	GreetingLastName = GreetingTemplate("{{user_last_name}}")
+
+	// GreetingFullName is replaced with user's full name
+	GreetingFullName = GreetingTemplate("{{user_full_name}}")
+)
+
+// greetingPlaceholderRe matches {{...}} placeholders in greeting text
+var greetingPlaceholderRe = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+
+// NewGreeting creates default greeting with text, error is returned if text has unknown {{...}} placeholder.
+// Set Locale of returned greeting for other locales
+func NewGreeting(text string) (Greeting, error) {
+	g := Greeting{Locale: string(LocaleMenuDefault), Text: text}
+	return g, g.validate()
+}
+
+// validate checks that greeting text has only known placeholders
+func (g Greeting) validate() error {
+	for _, p := range greetingPlaceholderRe.FindAllString(g.Text, -1) {
+		switch GreetingTemplate(p) {
+		case GreetingFirstName, GreetingLastName, GreetingFullName:
+		default:
+			return fmt.Errorf("%w: unknown placeholder %s", ErrInvalidGreeting, p)
+		}
+	}
+	return nil
+}
+
+// SetGreeting sets greeting text shown before user starts conversation, one greeting per locale
+func (msng *Messenger) SetGreeting(ctx context.Context, greetings []Greeting) error {
+	for _, g := range greetings {
+		if err := g.validate(); err != nil {
+			return err
+		}
+	}
+	return msng.setMessengerProfile(ctx, map[string]interface{}{"greeting": greetings})
+}
+
+// SetDefaultGreeting sets greeting text for all locales, text can contain GreetingTemplate placeholders
+func (msng *Messenger) SetDefaultGreeting(ctx context.Context, text string) error {
+	g, err := NewGreeting(text)
+	if err != nil {
+		return err
+	}
+	return msng.SetGreeting(ctx, []Greeting{g})
+}
+
+// GetStarted is Get Started button, payload is sent back in postback when user taps it
+type GetStarted struct {
+	Payload string `json:"payload"`
+}
+
+// GetMessengerProfile returns messenger profile fields, all MessengerProfileFields if fields is empty
+func (msng *Messenger) GetMessengerProfile(ctx context.Context, fields []string) (MessengerProfile, error) {
+	if len(fields) == 0 {
+		fields = MessengerProfileFields
+	}
+	var p MessengerProfile
+	err := msng.getMessengerProfile(ctx, fields, &p)
+	return p, err
+}
+
+// GreetingText returns greeting for locale, or default greeting if there is no greeting for locale
+func (p MessengerProfile) GreetingText(locale string) string {
+	text := ""
+	for _, g := range p.Greeting {
+		if g.Locale == locale {
+			return g.Text
+		}
+		if g.Locale == string(LocaleMenuDefault) {
+			text = g.Text
+		}
+	}
+	return text
+}
+
+// GetStartedPayload returns payload of Get Started button, empty if button is not set
+func (p MessengerProfile) GetStartedPayload() string {
+	if p.GetStarted == nil {
+		return ""
+	}
+	return p.GetStarted.Payload
+}
+
+// Menu returns persistent menu for locale, or default menu if there is no menu for locale
+func (p MessengerProfile) Menu(locale LocaleMenu) (PersistentMenu, bool) {
+	var menu PersistentMenu
+	found := false
+	for _, m := range p.PersistentMenu {
+		if m.Locale == locale {
+			return m, true
+		}
+		if m.Locale == LocaleMenuDefault {
+			menu, found = m, true
+		}
+	}
+	return menu, found
+}
+
+func (msng *Messenger) setMessengerProfile(ctx context.Context, fields map[string]interface{}) error {
+	return msng.graphRequest(ctx, http.MethodPost, messengerProfilePath, nil, fields, nil)
+}
+
+// getMessengerProfile decodes requested messenger profile fields into v
+func (msng *Messenger) getMessengerProfile(ctx context.Context, fields []string, v interface{}) error {
+	var reply struct {
+		Data []json.RawMessage `json:"data"`
+	}
+	q := url.Values{"fields": {strings.Join(fields, ",")}}
+	if err := msng.graphRequest(ctx, http.MethodGet, messengerProfilePath, q, nil, &reply); err != nil {
+		return err
+	}
+	if len(reply.Data) == 0 {
+		return nil // nothing set
+	}
+	return json.Unmarshal(reply.Data[0], v)
+}
+
+// DeleteMessengerProfileFields removes fields from messenger profile, e.g. "greeting" or "get_started"
+func (msng *Messenger) DeleteMessengerProfileFields(ctx context.Context, fields []string) error {
+	body := struct {
+		Fields []string `json:"fields"`
+	}{fields}
+	return msng.graphRequest(ctx, http.MethodDelete, messengerProfilePath, nil, body, nil)
+}
+
+// DeleteGreeting removes greeting text from messenger profile
+func (msng *Messenger) DeleteGreeting(ctx context.Context) error {
+	return msng.DeleteMessengerProfileFields(ctx, []string{"greeting"})
+}
+
+// DeleteGetStarted removes Get Started button from messenger profile
+func (msng *Messenger) DeleteGetStarted(ctx context.Context) error {
+	return msng.DeleteMessengerProfileFields(ctx, []string{"get_started"})
+}
+
+// DeleteIceBreakers removes ice breakers from messenger profile
+func (msng *Messenger) DeleteIceBreakers(ctx context.Context) error {
+	return msng.DeleteMessengerProfileFields(ctx, []string{"ice_breakers"})
+}
@@ -0,0 +1,244 @@
+package messenger_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestDeleteMessengerProfileFields(t *testing.T) {
+	fb.Reset()
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "")
+	for _, del := range []func(context.Context) error{msng.DeleteGreeting, msng.DeleteGetStarted, msng.DeletePersistentMenu, msng.DeleteIceBreakers} {
+		if err := del(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var deleted []string
+	for _, call := range fb.Calls() {
+		if call.Method != http.MethodDelete || call.Endpoint != "me/messenger_profile" {
+			t.Error("Unexpected request", call.Method, call.Endpoint)
+		}
+		var body struct {
+			Fields []string `json:"fields"`
+		}
+		if err := json.Unmarshal(call.Body, &body); err != nil {
+			t.Error(err)
+		}
+		deleted = append(deleted, body.Fields...)
+	}
+
+	expected := []string{"greeting", "get_started", "persistent_menu", "ice_breakers"}
+	if len(deleted) != len(expected) {
+		t.Fatal("Expected", expected, "deleted", deleted)
+	}
+	for i := range expected {
+		if deleted[i] != expected[i] {
+			t.Error("Expected", expected[i], "deleted", deleted[i])
+		}
+	}
+}
+
+func TestGetMessengerProfile(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/messenger_profile" || r.FormValue("fields") != "greeting,get_started,persistent_menu,ice_breakers" {
+			t.Error("Unexpected request", r.URL.Path, r.FormValue("fields"))
+		}
+		w.Write([]byte(`{"data":[{
+			"greeting":[{"locale":"default","text":"Hello!"},{"locale":"de_DE","text":"Hallo!"}],
+			"get_started":{"payload":"START"},
+			"persistent_menu":[{"locale":"default","composer_input_disabled":false,"call_to_actions":[{"type":"postback","title":"Help","payload":"HELP"}]}],
+			"ice_breakers":[{"question":"Where are you?","payload":"WHERE"}]
+		}]}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	p, err := msng.GetMessengerProfile(context.Background(), []string{"greeting", "get_started", "persistent_menu", "ice_breakers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.GreetingText("de_DE") != "Hallo!" || p.GreetingText("fr_FR") != "Hello!" {
+		t.Error("Unexpected greeting", p.Greeting)
+	}
+	if p.GetStartedPayload() != "START" {
+		t.Error("Unexpected get started", p.GetStarted)
+	}
+	if menu, ok := p.Menu("en_US"); !ok || menu.CallToActions[0].Payload != "HELP" {
+		t.Error("Unexpected menu", menu, ok)
+	}
+	if len(p.IceBreakers) == 0 || p.AccountLinkingURL != "" {
+		t.Error("Unexpected profile", p)
+	}
+}
+
+func TestValidateLocale(t *testing.T) {
+	tests := []struct {
+		locale string
+		valid  bool
+	}{
+		{"default", true},
+		{"en_US", true},
+		{"fr_FR", true},
+		{"en", false},
+		{"en_us", false},
+		{"EN_US", false},
+		{"en-US", false},
+		{"en_USA", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if valid := messenger.ValidateLocale(test.locale); valid != test.valid {
+			t.Errorf("Locale %q: expected %v, returned %v", test.locale, test.valid, valid)
+		}
+	}
+
+	for _, l := range messenger.SupportedLocales {
+		messenger.MustBeValidLocale(l)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for invalid locale")
+		}
+	}()
+	messenger.MustBeValidLocale("en")
+}
+
+func TestSyncProfile(t *testing.T) {
+	t.Parallel()
+	var updated []map[string]json.RawMessage
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var body map[string]json.RawMessage
+			json.NewDecoder(r.Body).Decode(&body)
+			updated = append(updated, body)
+			w.Write([]byte(`{"result":"success"}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"greeting":[{"locale":"default","text":"Hello!"}],"get_started":{"payload":"START"}}]}`))
+	})
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "", mock)
+	err := msng.SyncProfile(ctx, messenger.MessengerProfile{
+		Greeting:   []messenger.Greeting{{Locale: "default", Text: "Hello!"}},
+		GetStarted: &messenger.GetStarted{Payload: "GET_STARTED"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 1 || len(updated[0]) != 1 || string(updated[0]["get_started"]) != `{"payload":"GET_STARTED"}` {
+		t.Error("Expected only get started updated, sent", updated)
+	}
+
+	updated = nil
+	err = msng.SyncProfile(ctx, messenger.MessengerProfile{Greeting: []messenger.Greeting{{Locale: "default", Text: "Hello!"}}})
+	if err != nil || len(updated) != 0 {
+		t.Error("Expected no update for unchanged profile, sent", updated, err)
+	}
+
+	data, err := msng.ExportProfile(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := msng.ImportProfile(ctx, data); err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 1 || string(updated[0]["get_started"]) != `{"payload":"START"}` || updated[0]["greeting"] == nil {
+		t.Error("Expected exported profile imported, sent", updated)
+	}
+}
+
+func TestSetGreeting(t *testing.T) {
+	fb.Reset()
+
+	if _, err := messenger.NewGreeting("Hi " + string(messenger.GreetingFirstName) + "!"); err != nil {
+		t.Error("Expected {{user_first_name}} to be valid, returned", err)
+	}
+	if _, err := messenger.NewGreeting("Hi {{user_shoe_size}}!"); !errors.Is(err, messenger.ErrInvalidGreeting) {
+		t.Error("Expected ErrInvalidGreeting for {{user_shoe_size}}, returned", err)
+	}
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "")
+	if err := msng.SetGreeting(ctx, []messenger.Greeting{{Locale: "de_DE", Text: "Hallo {{user_shoe_size}}!"}}); !errors.Is(err, messenger.ErrInvalidGreeting) {
+		t.Error("Expected ErrInvalidGreeting, returned", err)
+	}
+	if len(fb.Calls()) != 0 {
+		t.Error("Expected no request for invalid greeting, sent", fb.Calls())
+	}
+
+	if err := msng.SetDefaultGreeting(ctx, "Hello {{user_full_name}}!"); err != nil {
+		t.Fatal(err)
+	}
+	call, _ := fb.LastCall()
+	if call.Endpoint != "me/messenger_profile" || string(call.Body) != `{"greeting":[{"locale":"default","text":"Hello {{user_full_name}}!"}]}` {
+		t.Error("Unexpected request", call.Endpoint, string(call.Body))
+	}
+}
+
+func TestSupportedLocales(t *testing.T) {
+	t.Parallel()
+	var methods []string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.URL.Path != "/me/messenger_profile" {
+			t.Error("Unexpected path", r.URL.Path)
+		}
+		switch r.Method {
+		case http.MethodPost:
+			var body struct {
+				SupportedLocales []string `json:"supported_locales"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.SupportedLocales) != 2 || body.SupportedLocales[1] != "de_DE" {
+				t.Error("Unexpected supported locales", body, err)
+			}
+		case http.MethodGet:
+			if r.FormValue("fields") != "supported_locales" {
+				t.Error("Unexpected fields", r.FormValue("fields"))
+			}
+			w.Write([]byte(`{"data":[{"supported_locales":["en_US","de_DE"]}]}`))
+			return
+		case http.MethodDelete:
+			var body struct {
+				Fields []string `json:"fields"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Fields) != 1 || body.Fields[0] != "supported_locales" {
+				t.Error("Unexpected deleted fields", body, err)
+			}
+		}
+		w.Write([]byte(`{"result":"success"}`))
+	})
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "", mock)
+	if err := msng.SetSupportedLocales(ctx, []string{"en_US", "german"}); !errors.Is(err, messenger.ErrInvalidLocale) {
+		t.Error("Expected ErrInvalidLocale, returned", err)
+	}
+	if len(methods) != 0 {
+		t.Error("Expected invalid locales not to be sent")
+	}
+
+	if err := msng.SetSupportedLocales(ctx, []string{"en_US", "de_DE"}); err != nil {
+		t.Fatal(err)
+	}
+	locales, err := msng.GetSupportedLocales(ctx)
+	if err != nil || len(locales) != 2 || locales[0] != "en_US" {
+		t.Error("Unexpected supported locales", locales, err)
+	}
+	if err := msng.DeleteSupportedLocales(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(methods, ",") != "POST,GET,DELETE" {
+		t.Error("Unexpected requests", methods)
+	}
+}
@@ -0,0 +1,109 @@
+package messenger
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultProfileCacheTTL is time user profile is cached for when ProfileCache TTL is not set
+const DefaultProfileCacheTTL = 24 * time.Hour
+
+// CachedProfileStore returns user profiles, fetching them from Facebook only when not cached, see ProfileCache
+type CachedProfileStore interface {
+	Profile(ctx context.Context, userID string) (UserProfile, error)
+}
+
+// ProfileCache is CachedProfileStore that keeps user profiles in memory, expired profiles are removed
+type ProfileCache struct {
+	msng *Messenger
+	ttl  time.Duration
+
+	mu        sync.Mutex
+	profiles  map[string]*cachedProfile
+	lastSweep time.Time
+}
+
+// cachedProfile is set when done is closed, concurrent callers wait for the same fetch
+type cachedProfile struct {
+	done    chan struct{}
+	profile UserProfile
+	err     error
+	expires time.Time
+}
+
+// expired returns true if profile is fetched and cache expired
+func (cp *cachedProfile) expired() bool {
+	select {
+	case <-cp.done:
+		return time.Now().After(cp.expires)
+	default:
+		return false
+	}
+}
+
+// profileCacheFields are user profile fields fetched by ProfileCache
+var profileCacheFields = []ProfileField{ProfileFieldFirstName, ProfileFieldLastName, ProfileFieldProfilePic, ProfileFieldLocale, ProfileFieldTimezone}
+
+// NewProfileCache creates cache of user profiles fetched with msng, profiles are fetched again after ttl.
+// If ttl is 0, DefaultProfileCacheTTL is used. In event middleware profiles are fetched with messenger
+// handling the event, so cache can be shared by messengers of different pages, see Messenger.Clone
+func NewProfileCache(msng *Messenger, ttl time.Duration) *ProfileCache {
+	if ttl <= 0 {
+		ttl = DefaultProfileCacheTTL
+	}
+	return &ProfileCache{msng: msng, ttl: ttl, profiles: map[string]*cachedProfile{}, lastSweep: time.Now()}
+}
+
+// Profile returns cached profile of userID, profile is fetched if it's not cached or cache expired.
+// Profile is fetched once for concurrent calls for the same user, failed fetches are not cached
+func (c *ProfileCache) Profile(ctx context.Context, userID string) (UserProfile, error) {
+	msng := c.msng
+	if m, ok := ctx.Value(eventMessengerKey{}).(*Messenger); ok {
+		msng = m
+	}
+	key := msng.PageID + "/" + userID
+
+	c.mu.Lock()
+	c.sweep()
+	cp, ok := c.profiles[key]
+	if ok && !cp.expired() {
+		c.mu.Unlock()
+		select {
+		case <-cp.done:
+			return cp.profile, cp.err
+		case <-ctx.Done():
+			return UserProfile{}, ctx.Err()
+		}
+	}
+	cp = &cachedProfile{done: make(chan struct{})}
+	c.profiles[key] = cp
+	c.mu.Unlock()
+
+	cp.profile, cp.err = msng.GetUserProfileFields(ctx, userID, profileCacheFields...)
+	cp.expires = time.Now().Add(c.ttl)
+	if cp.err != nil {
+		c.mu.Lock()
+		if c.profiles[key] == cp {
+			delete(c.profiles, key)
+		}
+		c.mu.Unlock()
+	}
+	close(cp.done)
+	return cp.profile, cp.err
+}
+
+// sweep removes expired profiles at most once per ttl, so cache doesn't grow with every user ever seen.
+// It is called with mu locked
+func (c *ProfileCache) sweep() {
+	now := time.Now()
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+	for key, cp := range c.profiles {
+		if cp.expired() {
+			delete(c.profiles, key)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package messenger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProfileCacheSweep(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"first_name":"John","locale":"en_US"}`))
+	}))
+	defer s.Close()
+
+	msng := New("XXXXXXX", "1", WithTestURL(s.URL))
+	c := NewProfileCache(&msng, 10*time.Millisecond)
+	ctx := context.Background()
+	for _, userID := range []string{"1", "2"} {
+		if _, err := c.Profile(ctx, userID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, err := c.Profile(ctx, "3"); err != nil {
+		t.Fatal(err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.profiles["1/3"]; len(c.profiles) != 1 || !ok {
+		t.Error("Expected expired profiles removed, cached", len(c.profiles))
+	}
+}
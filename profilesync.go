@@ -0,0 +1,78 @@
+package messenger
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+)
+
+// SyncProfile updates messenger profile to match profile. Current profile is read first and only fields
+// that differ are sent, fields not set in profile are left as they are
+func (msng *Messenger) SyncProfile(ctx context.Context, profile MessengerProfile) error {
+	desired, err := profileFields(profile)
+	if err != nil {
+		return err
+	}
+	if len(desired) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	current, err := msng.GetMessengerProfile(ctx, names)
+	if err != nil {
+		return err
+	}
+	currentFields, err := profileFields(current)
+	if err != nil {
+		return err
+	}
+
+	changed := map[string]interface{}{}
+	for name, v := range desired {
+		if !reflect.DeepEqual(v, currentFields[name]) {
+			changed[name] = v
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return msng.setMessengerProfile(ctx, changed)
+}
+
+// profileFields returns JSON decoded fields that are set in profile
+func profileFields(p MessengerProfile) (map[string]interface{}, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	err = json.Unmarshal(b, &fields)
+	return fields, err
+}
+
+// ExportProfile returns messenger profile as JSON, it can be restored with ImportProfile
+func (msng *Messenger) ExportProfile(ctx context.Context) (json.RawMessage, error) {
+	var data json.RawMessage
+	if err := msng.getMessengerProfile(ctx, MessengerProfileFields, &data); err != nil {
+		return nil, err
+	}
+	if data == nil {
+		data = json.RawMessage("{}")
+	}
+	return data, nil
+}
+
+// ImportProfile sets messenger profile fields from JSON exported with ExportProfile
+func (msng *Messenger) ImportProfile(ctx context.Context, data json.RawMessage) error {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return msng.setMessengerProfile(ctx, fields)
+}
@@ -0,0 +1,58 @@
+package messenger
+
+import "encoding/json"
+
+// QuickReplyContentTypeText is content type of quick reply with title and payload
+const QuickReplyContentTypeText = "text"
+
+// QuickReply is button shown above composer, payload is sent back in FacebookMessage.QuickReply when user taps it
+type QuickReply struct {
+	ContentType string `json:"content_type"`
+	Title       string `json:"title,omitempty"`
+	Payload     string `json:"payload,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+}
+
+// NewQuickReply creates text quick reply with title and payload
+func NewQuickReply(title, payload string) QuickReply {
+	return QuickReply{ContentType: QuickReplyContentTypeText, Title: title, Payload: payload}
+}
+
+// WithQuickReplies adds quick replies to the message, up to 13
+func WithQuickReplies(qrs ...QuickReply) SendOption {
+	return func(o *sendOptions) {
+		o.setMessage("quick_replies", qrs)
+	}
+}
+
+// nlpPayload is quick reply payload with expected NLP intent
+type nlpPayload struct {
+	Intent     string  `json:"intent"`
+	Confidence float64 `json:"confidence"`
+}
+
+// NLPQuickReply creates quick reply with payload encoding expected NLP intent, e.g. wit.ai intent,
+// so tapping it can be handled like message with that intent. Read it with NLPIntent
+func NLPQuickReply(title, intent string, confidence float64) QuickReply {
+	b, _ := json.Marshal(nlpPayload{Intent: intent, Confidence: confidence})
+	return NewQuickReply(title, string(b))
+}
+
+// NLPIntent returns intent and confidence encoded in payload by NLPQuickReply, ok is false for other payloads
+func (qr QuickReply) NLPIntent() (intent string, confidence float64, ok bool) {
+	return decodeNLPPayload(qr.Payload)
+}
+
+// NLPIntent returns intent and confidence of tapped quick reply created with NLPQuickReply,
+// ok is false for other payloads
+func (qr FacebookQuickReply) NLPIntent() (intent string, confidence float64, ok bool) {
+	return decodeNLPPayload(qr.Payload)
+}
+
+func decodeNLPPayload(payload string) (string, float64, bool) {
+	var p nlpPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil || p.Intent == "" {
+		return "", 0, false
+	}
+	return p.Intent, p.Confidence, true
+}
@@ -0,0 +1,40 @@
+package messenger_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestNLPQuickReply(t *testing.T) {
+	qr := messenger.NLPQuickReply("Book a table", "book_table", 0.9)
+	if qr.Payload != `{"intent":"book_table","confidence":0.9}` {
+		t.Error("Unexpected payload", qr.Payload)
+	}
+	if intent, confidence, ok := qr.NLPIntent(); !ok || intent != "book_table" || confidence != 0.9 {
+		t.Error("Unexpected intent", intent, confidence, ok)
+	}
+	received := messenger.FacebookQuickReply{Payload: qr.Payload}
+	if intent, _, ok := received.NLPIntent(); !ok || intent != "book_table" {
+		t.Error("Unexpected received intent", intent, ok)
+	}
+	if _, _, ok := messenger.NewQuickReply("Yes", "YES").NLPIntent(); ok {
+		t.Error("Expected no intent in plain payload")
+	}
+}
+
+func TestWithQuickReplies(t *testing.T) {
+	fb.Reset()
+
+	msng := messenger.New("XXXXXXX", "")
+	m := msng.NewTextMessage(42, "Hungry?")
+	if _, err := msng.SendMessage(&m, messenger.WithQuickReplies(messenger.NLPQuickReply("Book a table", "book_table", 1))); err != nil {
+		t.Fatal(err)
+	}
+	call, _ := fb.LastCall()
+	expected := `"quick_replies":[{"content_type":"text","title":"Book a table","payload":"{\"intent\":\"book_table\",\"confidence\":1}"}]`
+	if !strings.Contains(string(call.Body), expected) {
+		t.Error("Expected", expected, "sent", string(call.Body))
+	}
+}
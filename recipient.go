@@ -0,0 +1,51 @@
+package messenger
+
+import "encoding/json"
+
+// Recipient identifies who message is sent to, pass it to SendMessage with WithRecipient
+type Recipient interface {
+	recipientJSON() ([]byte, error)
+}
+
+// PSIDRecipient is user identified by page scoped ID
+type PSIDRecipient struct {
+	ID string `json:"id"`
+}
+
+// PhoneRecipient is user identified by phone number, requires pages_messaging_phone_number permission
+type PhoneRecipient struct {
+	Phone string `json:"phone_number"`
+}
+
+// UserRefRecipient is user identified by user_ref received from checkbox plugin
+type UserRefRecipient struct {
+	UserRef string `json:"user_ref"`
+}
+
+// PostIDRecipient is author of page post, used for private replies
+type PostIDRecipient struct {
+	PostID string `json:"post_id"`
+}
+
+// CommentIDRecipient is author of comment on page post, used for private replies
+type CommentIDRecipient struct {
+	CommentID string `json:"comment_id"`
+}
+
+func (r PSIDRecipient) recipientJSON() ([]byte, error)      { return json.Marshal(r) }
+func (r PhoneRecipient) recipientJSON() ([]byte, error)     { return json.Marshal(r) }
+func (r UserRefRecipient) recipientJSON() ([]byte, error)   { return json.Marshal(r) }
+func (r PostIDRecipient) recipientJSON() ([]byte, error)    { return json.Marshal(r) }
+func (r CommentIDRecipient) recipientJSON() ([]byte, error) { return json.Marshal(r) }
+
+// WithRecipient sends message to recipient r instead of recipient set in the message
+func WithRecipient(r Recipient) SendOption {
+	return func(o *sendOptions) {
+		b, err := r.recipientJSON()
+		if err != nil {
+			o.fail(err)
+			return
+		}
+		o.set("recipient", json.RawMessage(b))
+	}
+}
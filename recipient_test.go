@@ -0,0 +1,38 @@
+package messenger_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestWithRecipient(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+	m := msng.NewTextMessage(1, "Hello")
+
+	tests := []struct {
+		r    messenger.Recipient
+		want string
+	}{
+		{messenger.PSIDRecipient{ID: "42"}, `{"id":"42"}`},
+		{messenger.PhoneRecipient{Phone: "+1(212)555-2368"}, `{"phone_number":"+1(212)555-2368"}`},
+		{messenger.UserRefRecipient{UserRef: "REF"}, `{"user_ref":"REF"}`},
+		{messenger.PostIDRecipient{PostID: "POST"}, `{"post_id":"POST"}`},
+		{messenger.CommentIDRecipient{CommentID: "COMMENT"}, `{"comment_id":"COMMENT"}`},
+	}
+	for _, test := range tests {
+		fb.Reset()
+		if _, err := msng.SendMessage(&m, messenger.WithRecipient(test.r)); err != nil {
+			t.Fatal(err)
+		}
+		call, _ := fb.LastCall()
+		var body struct {
+			Recipient json.RawMessage `json:"recipient"`
+		}
+		json.Unmarshal(call.Body, &body)
+		if string(body.Recipient) != test.want {
+			t.Errorf("Expected recipient %s, sent %s", test.want, body.Recipient)
+		}
+	}
+}
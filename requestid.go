@@ -0,0 +1,38 @@
+package messenger
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// WithRequestIDHeader sets header (e.g. X-Request-ID or X-Correlation-ID) that is read from webhook request
+// and sent with messages sent by event handlers, so they can be correlated in distributed traces.
+// If webhook request has no such header, new UUID is generated
+func WithRequestIDHeader(header string) Option {
+	return func(msng *Messenger) {
+		msng.RequestIDHeader = header
+	}
+}
+
+// withRequestID returns copy of messenger with request ID of webhook request r
+func (msng *Messenger) withRequestID(r *http.Request) *Messenger {
+	if msng.RequestIDHeader == "" {
+		return msng
+	}
+	m := *msng
+	m.requestID = r.Header.Get(msng.RequestIDHeader)
+	if m.requestID == "" {
+		m.requestID = newUUID()
+	}
+	return &m
+}
+
+// newUUID returns random (version 4) UUID
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
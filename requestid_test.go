@@ -0,0 +1,45 @@
+package messenger_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestRequestIDHeader(t *testing.T) {
+	t.Parallel()
+	ids := make(chan string, 1)
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		ids <- r.Header.Get("X-Request-ID")
+		w.Write([]byte(`{"recipient_id":"12123213123","message_id":"mid.1"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock, messenger.WithRequestIDHeader("X-Request-ID"))
+	msng.MessageReceived = func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) {
+		msng.SendTextMessageStr(context.Background(), "12123213123", "reply")
+	}
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage))
+	r.Header.Set("X-Request-ID", "req-42")
+	msng.ServeHTTP(httptest.NewRecorder(), r)
+	if id := <-ids; id != "req-42" {
+		t.Error("Expected request ID req-42, sent", id)
+	}
+
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage)))
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if id := <-ids; !uuid.MatchString(id) {
+		t.Error("Expected generated UUID, sent", id)
+	}
+
+	// messages sent outside of event handlers have no request ID
+	msng.SendTextMessageStr(context.Background(), "12123213123", "hello")
+	if id := <-ids; id != "" {
+		t.Error("Expected no request ID, sent", id)
+	}
+}
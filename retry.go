@@ -0,0 +1,230 @@
+package messenger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// MaxRetryAttempts is number of attempts after which message is dropped from RetryQueue
+var MaxRetryAttempts = 5
+
+// retryPollInterval is how often retry worker checks RetryQueue for messages ready to be resent
+var retryPollInterval = time.Second
+
+// RetryItem is message waiting in RetryQueue
+type RetryItem struct {
+	ID          string `json:"id"`
+	RecipientID string `json:"recipient_id"`
+
+	// Body is JSON encoded message with send options applied, it is resent as is
+	Body json.RawMessage `json:"body"`
+
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
+
+// RetryQueue stores messages that failed to send. Dequeue returns item ready to be resent (NextAttemptAt has passed),
+// ok is false if there is none. Dequeued item must be acknowledged with Ack when sent,
+// or with Nack when sending failed again, so it is rescheduled with back-off.
+//
+// MemoryRetryQueue loses messages on restart, implement RetryQueue with
+// disk backed store (like BoltDB, SQL or Redis) if messages must survive restarts
+type RetryQueue interface {
+	Enqueue(item RetryItem) error
+	Dequeue() (item RetryItem, ok bool, err error)
+	Ack(id string) error
+	Nack(id string) error
+}
+
+// WithRetryQueue sets queue for messages that failed to send, start StartRetryWorker to resend them
+func WithRetryQueue(q RetryQueue) Option {
+	return func(msng *Messenger) {
+		msng.RetryQueue = q
+	}
+}
+
+// MemoryRetryQueue is RetryQueue kept in memory
+type MemoryRetryQueue struct {
+	mu       sync.Mutex
+	items    []RetryItem
+	inFlight map[string]RetryItem
+}
+
+// NewMemoryRetryQueue creates empty MemoryRetryQueue
+func NewMemoryRetryQueue() *MemoryRetryQueue {
+	return &MemoryRetryQueue{inFlight: map[string]RetryItem{}}
+}
+
+// Enqueue adds item to the queue
+func (q *MemoryRetryQueue) Enqueue(item RetryItem) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.items = append(q.items, item)
+	return nil
+}
+
+// Dequeue returns first item ready to be resent
+func (q *MemoryRetryQueue) Dequeue() (RetryItem, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	for i, item := range q.items {
+		if item.NextAttemptAt.After(now) {
+			continue
+		}
+		q.items = append(q.items[:i], q.items[i+1:]...)
+		q.inFlight[item.ID] = item
+		return item, true, nil
+	}
+	return RetryItem{}, false, nil
+}
+
+// Ack removes dequeued item from the queue
+func (q *MemoryRetryQueue) Ack(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.inFlight[id]; !ok {
+		return ErrNotFound
+	}
+	delete(q.inFlight, id)
+	return nil
+}
+
+// Nack returns dequeued item to the queue, next attempt is delayed with exponential back-off
+func (q *MemoryRetryQueue) Nack(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	item, ok := q.inFlight[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(q.inFlight, id)
+	item.Attempts++
+	item.NextAttemptAt = time.Now().Add(retryBackoff(item.Attempts))
+	q.items = append(q.items, item)
+	return nil
+}
+
+// Len returns number of items in the queue, including dequeued items that are not acknowledged yet
+func (q *MemoryRetryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items) + len(q.inFlight)
+}
+
+// retryBackoff returns delay before next attempt, doubled with every attempt up to one hour
+func retryBackoff(attempts int) time.Duration {
+	if attempts > 12 {
+		return time.Hour
+	}
+	d := time.Second << attempts
+	if d > time.Hour {
+		return time.Hour
+	}
+	return d
+}
+
+// StartRetryWorker resends messages from messenger's RetryQueue in background until ctx is cancelled.
+// Messages are dropped after MaxRetryAttempts attempts or if error is not transient
+func StartRetryWorker(ctx context.Context, msng *Messenger) error {
+	if msng.RetryQueue == nil {
+		return ErrNoRetryQueue
+	}
+	go func() {
+		ticker := time.NewTicker(retryPollInterval)
+		defer ticker.Stop()
+		for {
+			msng.processRetries(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// processRetries resends all messages that are ready
+func (msng *Messenger) processRetries(ctx context.Context) {
+	q := msng.RetryQueue
+	for ctx.Err() == nil {
+		item, ok, err := q.Dequeue()
+		if err != nil {
+			msng.logger().Error("retry queue dequeue failed", "error", err)
+			return
+		}
+		if !ok {
+			return
+		}
+
+		_, err = msng.send(ctx, retryMessage{recipientID: item.RecipientID, body: item.Body}, nil)
+		switch {
+		case err == nil:
+			q.Ack(item.ID)
+		case isTransient(err) && item.Attempts+1 < MaxRetryAttempts:
+			q.Nack(item.ID)
+		default:
+			msng.logger().Error("message dropped from retry queue", "recipient_id", item.RecipientID, "attempts", item.Attempts+1, "error", err)
+			q.Ack(item.ID)
+		}
+	}
+}
+
+// enqueueRetry adds message m with send options applied to RetryQueue
+func (msng *Messenger) enqueueRetry(m Message, opts []SendOption) {
+	body, err := encodeMessage(m, msng.withDefaultPersona(m, opts))
+	if err != nil {
+		msng.logger().Error("message not enqueued for retry", "error", err)
+		return
+	}
+
+	recipientID, _ := messageInfo(m)
+	item := RetryItem{
+		ID:            newRetryID(),
+		RecipientID:   recipientID,
+		Body:          body,
+		NextAttemptAt: time.Now().Add(retryBackoff(0)),
+	}
+	if err := msng.RetryQueue.Enqueue(item); err != nil {
+		msng.logger().Error("message not enqueued for retry", "recipient_id", recipientID, "error", err)
+	}
+}
+
+// retryMessage is message from RetryQueue, already encoded with send options applied
+type retryMessage struct {
+	recipientID string
+	body        json.RawMessage
+}
+
+func (m retryMessage) foo() {} // Message interface
+
+// MarshalJSON returns encoded message
+func (m retryMessage) MarshalJSON() ([]byte, error) { return m.body, nil }
+
+func (m retryMessage) messageInfo() (string, string) { return m.recipientID, "retry" }
+
+// isTransient reports if sending might succeed when retried, on network errors and rate limiting
+func isTransient(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return !errors.Is(err, context.Canceled)
+	}
+	var apiErr FacebookAPIError
+	if errors.As(err, &apiErr) {
+		return errors.Is(err, ErrRateLimited) || apiErr.HasCode(ErrCodeAPIUnknown) || apiErr.HasCode(ErrCodeAPIService)
+	}
+	return false
+}
+
+func newRetryID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
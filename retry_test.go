@@ -0,0 +1,146 @@
+package messenger_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestRetryQueue(t *testing.T) {
+	t.Parallel()
+	fail := true
+	sent := make(chan struct{}, 1)
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.Write([]byte(`{"error":{"message":"Calls to this api have exceeded the rate limit.","type":"OAuthException","code":613}}`))
+			return
+		}
+		w.Write([]byte(`{"recipient_id":"123","message_id":"mid.1"}`))
+		sent <- struct{}{}
+	})
+
+	q := messenger.NewMemoryRetryQueue()
+	msng := messenger.New("XXXXXXX", "", mock, messenger.WithRetryQueue(q))
+
+	if _, err := msng.SendTextMessageStr(context.Background(), "123", "hello"); err == nil {
+		t.Fatal("Expected rate limit error")
+	}
+	if q.Len() != 1 {
+		t.Fatal("Expected failed message in retry queue, queue length", q.Len())
+	}
+	if _, ok, _ := q.Dequeue(); ok {
+		t.Error("Expected message not to be ready before back-off")
+	}
+
+	// policy errors are not transient, they are not retried
+	m := msng.NewTextMessage(123, "hello")
+	m.Tag = messenger.MessageTagAccountUpdate
+	msng.SendMessage(&m)
+	if q.Len() != 1 {
+		t.Error("Expected invalid message not to be enqueued, queue length", q.Len())
+	}
+
+	q.Enqueue(messenger.RetryItem{ID: "ready", RecipientID: "123", Body: json.RawMessage(`{"recipient":{"id":"123"},"message":{"text":"hello"}}`)})
+
+	fail = false
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := messenger.StartRetryWorker(ctx, &msng); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("Ready message not resent")
+	}
+}
+
+func TestMemoryRetryQueueNack(t *testing.T) {
+	q := messenger.NewMemoryRetryQueue()
+	q.Enqueue(messenger.RetryItem{ID: "1"})
+
+	item, ok, err := q.Dequeue()
+	if !ok || err != nil || item.ID != "1" {
+		t.Fatal("Expected item 1, returned", item, ok, err)
+	}
+	if _, ok, _ := q.Dequeue(); ok {
+		t.Error("Expected dequeued item not to be returned again")
+	}
+
+	if err := q.Nack("1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := q.Dequeue(); ok {
+		t.Error("Expected nacked item to wait for back-off")
+	}
+	if q.Len() != 1 {
+		t.Error("Expected nacked item in queue, queue length", q.Len())
+	}
+
+	if err := q.Ack("unknown"); err != messenger.ErrNotFound {
+		t.Error("Expected ErrNotFound, returned", err)
+	}
+}
+
+func TestStartRetryWorkerWithoutQueue(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+	if err := messenger.StartRetryWorker(context.Background(), &msng); err != messenger.ErrNoRetryQueue {
+		t.Error("Expected ErrNoRetryQueue, returned", err)
+	}
+}
+
+func TestRetryResendsEncodedBody(t *testing.T) {
+	t.Parallel()
+	fail := true
+	bodies := make(chan string, 2)
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies <- string(b)
+		if fail {
+			w.Write([]byte(`{"error":{"message":"Calls to this api have exceeded the rate limit.","type":"OAuthException","code":613}}`))
+			return
+		}
+		w.Write([]byte(`{"recipient_id":"123","message_id":"mid.1"}`))
+	})
+
+	q := readyRetryQueue{messenger.NewMemoryRetryQueue()}
+	msng := messenger.New("XXXXXXX", "", mock, messenger.WithRetryQueue(q), messenger.WithDefaultPersona("DEFAULT_PERSONA"))
+
+	m, _ := messenger.NewTemplateMessage("", messenger.GenericElement{Title: "Pizza"})
+	if _, err := msng.SendMessage(m, messenger.WithRecipient(messenger.PhoneRecipient{Phone: "+1(212)555-2368"}), messenger.WithPersona("AGENT")); err == nil {
+		t.Fatal("Expected rate limit error")
+	}
+	sent := <-bodies
+	if q.Len() != 1 {
+		t.Fatal("Expected template message in retry queue, queue length", q.Len())
+	}
+
+	fail = false
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	messenger.StartRetryWorker(ctx, &msng)
+	select {
+	case resent := <-bodies:
+		if resent != sent || !strings.Contains(resent, `"persona_id":"AGENT"`) || !strings.Contains(resent, `"phone_number"`) {
+			t.Error("Expected", sent, "resent", resent)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Message not resent")
+	}
+}
+
+// readyRetryQueue makes enqueued items ready to be resent immediately
+type readyRetryQueue struct {
+	*messenger.MemoryRetryQueue
+}
+
+func (q readyRetryQueue) Enqueue(item messenger.RetryItem) error {
+	item.NextAttemptAt = time.Time{}
+	return q.MemoryRetryQueue.Enqueue(item)
+}
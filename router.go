@@ -0,0 +1,146 @@
+package messenger
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MessageHandler handles received message, it can be set as MessageReceived
+type MessageHandler func(msng *Messenger, userID int64, m FacebookMessage)
+
+type prefixMessageRoute struct {
+	prefix  string
+	handler MessageHandler
+}
+
+// QuickReplyRouter routes received messages to handlers by quick reply payload.
+// Exact payload matches are checked first, then prefixes in order they were added
+type QuickReplyRouter struct {
+	exact    map[string]MessageHandler
+	prefixes []prefixMessageRoute
+	fallback MessageHandler
+}
+
+// NewQuickReplyRouter creates empty QuickReplyRouter
+func NewQuickReplyRouter() *QuickReplyRouter {
+	return &QuickReplyRouter{exact: map[string]MessageHandler{}}
+}
+
+// Handle routes quick replies with payload to handler
+func (r *QuickReplyRouter) Handle(payload string, handler MessageHandler) *QuickReplyRouter {
+	r.exact[payload] = handler
+	return r
+}
+
+// HandlePrefix routes quick replies with payload starting with prefix to handler
+func (r *QuickReplyRouter) HandlePrefix(prefix string, handler MessageHandler) *QuickReplyRouter {
+	r.prefixes = append(r.prefixes, prefixMessageRoute{prefix, handler})
+	return r
+}
+
+// Default sets handler for messages without quick reply or with unknown payload
+func (r *QuickReplyRouter) Default(handler MessageHandler) *QuickReplyRouter {
+	r.fallback = handler
+	return r
+}
+
+// MessageHandler returns handler that routes messages, set it as MessageReceived
+func (r *QuickReplyRouter) MessageHandler() func(msng *Messenger, userID int64, m FacebookMessage) {
+	return func(msng *Messenger, userID int64, m FacebookMessage) {
+		if h := r.route(m); h != nil {
+			h(msng, userID, m)
+		}
+	}
+}
+
+func (r *QuickReplyRouter) route(m FacebookMessage) MessageHandler {
+	if m.QuickReply == nil {
+		return r.fallback
+	}
+	if h, ok := r.exact[m.QuickReply.Payload]; ok {
+		return h
+	}
+	for _, p := range r.prefixes {
+		if strings.HasPrefix(m.QuickReply.Payload, p.prefix) {
+			return p.handler
+		}
+	}
+	return r.fallback
+}
+
+// PostbackHandler handles received postback, it can be set as PostbackReceived
+type PostbackHandler func(msng *Messenger, userID int64, p FacebookPostback)
+
+type prefixPostbackRoute struct {
+	prefix  string
+	handler PostbackHandler
+}
+
+type regexpPostbackRoute struct {
+	re      *regexp.Regexp
+	handler func(msng *Messenger, userID int64, p FacebookPostback, groups []string)
+}
+
+// PostbackRouter routes received postbacks to handlers by payload. Exact payload matches are checked first,
+// then prefixes and regular expressions in order they were added
+type PostbackRouter struct {
+	exact    map[string]PostbackHandler
+	prefixes []prefixPostbackRoute
+	regexps  []regexpPostbackRoute
+	fallback PostbackHandler
+}
+
+// NewPostbackRouter creates empty PostbackRouter
+func NewPostbackRouter() *PostbackRouter {
+	return &PostbackRouter{exact: map[string]PostbackHandler{}}
+}
+
+// Handle routes postbacks with payload to handler
+func (r *PostbackRouter) Handle(payload string, handler PostbackHandler) *PostbackRouter {
+	r.exact[payload] = handler
+	return r
+}
+
+// HandlePrefix routes postbacks with payload starting with prefix to handler
+func (r *PostbackRouter) HandlePrefix(prefix string, handler PostbackHandler) *PostbackRouter {
+	r.prefixes = append(r.prefixes, prefixPostbackRoute{prefix, handler})
+	return r
+}
+
+// HandleRegexp routes postbacks with payload matching re to handler, handler receives submatches of re
+// (groups[0] is whole payload)
+func (r *PostbackRouter) HandleRegexp(re *regexp.Regexp, handler func(msng *Messenger, userID int64, p FacebookPostback, groups []string)) *PostbackRouter {
+	r.regexps = append(r.regexps, regexpPostbackRoute{re, handler})
+	return r
+}
+
+// Default sets handler for postbacks with unknown payload
+func (r *PostbackRouter) Default(handler PostbackHandler) *PostbackRouter {
+	r.fallback = handler
+	return r
+}
+
+// Handler returns handler that routes postbacks, set it as PostbackReceived
+func (r *PostbackRouter) Handler() func(msng *Messenger, userID int64, p FacebookPostback) {
+	return func(msng *Messenger, userID int64, p FacebookPostback) {
+		if h, ok := r.exact[p.Payload]; ok {
+			h(msng, userID, p)
+			return
+		}
+		for _, route := range r.prefixes {
+			if strings.HasPrefix(p.Payload, route.prefix) {
+				route.handler(msng, userID, p)
+				return
+			}
+		}
+		for _, route := range r.regexps {
+			if groups := route.re.FindStringSubmatch(p.Payload); groups != nil {
+				route.handler(msng, userID, p, groups)
+				return
+			}
+		}
+		if r.fallback != nil {
+			r.fallback(msng, userID, p)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package messenger_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestQuickReplyRouter(t *testing.T) {
+	var routed string
+	handler := func(name string) messenger.MessageHandler {
+		return func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) {
+			routed = name
+		}
+	}
+
+	r := messenger.NewQuickReplyRouter().
+		Handle("COLOR_RED", handler("red")).
+		HandlePrefix("COLOR_", handler("color")).
+		HandlePrefix("SIZE_", handler("size")).
+		Default(handler("default"))
+	h := r.MessageHandler()
+
+	tests := []struct {
+		payload string
+		want    string
+	}{
+		{"COLOR_RED", "red"},
+		{"COLOR_BLUE", "color"},
+		{"SIZE_XL", "size"},
+		{"OTHER", "default"},
+		{"", "default"},
+	}
+	for _, test := range tests {
+		routed = ""
+		m := messenger.FacebookMessage{Text: "tap"}
+		if test.payload != "" {
+			m.QuickReply = &messenger.FacebookQuickReply{Payload: test.payload}
+		}
+		h(nil, 1, m)
+		if routed != test.want {
+			t.Errorf("Payload %q: expected %s, routed to %q", test.payload, test.want, routed)
+		}
+	}
+}
+
+func TestPostbackRouter(t *testing.T) {
+	var routed string
+	var groups []string
+	handler := func(name string) messenger.PostbackHandler {
+		return func(msng *messenger.Messenger, userID int64, p messenger.FacebookPostback) {
+			routed = name
+		}
+	}
+
+	h := messenger.NewPostbackRouter().
+		Handle("GET_STARTED", handler("start")).
+		HandlePrefix("BUY_", handler("buy")).
+		HandleRegexp(regexp.MustCompile(`^ORDER_(\d+)_(\w+)$`), func(msng *messenger.Messenger, userID int64, p messenger.FacebookPostback, g []string) {
+			routed, groups = "order", g
+		}).
+		Default(handler("default")).
+		Handler()
+
+	tests := []struct {
+		payload string
+		want    string
+	}{
+		{"GET_STARTED", "start"},
+		{"BUY_42", "buy"},
+		{"ORDER_42_CANCEL", "order"},
+		{"ORDER_X", "default"},
+	}
+	for _, test := range tests {
+		routed = ""
+		h(nil, 1, messenger.FacebookPostback{Payload: test.payload})
+		if routed != test.want {
+			t.Errorf("Payload %q: expected %s, routed to %q", test.payload, test.want, routed)
+		}
+	}
+
+	h(nil, 1, messenger.FacebookPostback{Payload: "ORDER_42_CANCEL"})
+	if len(groups) != 3 || groups[1] != "42" || groups[2] != "CANCEL" {
+		t.Error("Unexpected capture groups", groups)
+	}
+}
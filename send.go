@@ -0,0 +1,202 @@
+package messenger
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// maxMetadataLength is maximum length of message metadata
+const maxMetadataLength = 1000
+
+// SendOption changes message before sending, pass them to SendMessage
+type SendOption func(o *sendOptions)
+
+type sendOptions struct {
+	fields        map[string]interface{} // top level fields set in message JSON
+	messageFields map[string]interface{} // fields set in "message" object of message JSON
+	transforms    []func(body map[string]interface{}) error
+	deadline      time.Duration // send timeout, see WithRequestDeadline
+	timeout       time.Duration // send timeout overriding Messenger.SendTimeout, see WithCallTimeout
+	err           error         // first error from options, returned by SendMessage
+}
+
+func (o *sendOptions) set(field string, v interface{}) {
+	if o.fields == nil {
+		o.fields = map[string]interface{}{}
+	}
+	o.fields[field] = v
+}
+
+func (o *sendOptions) setMessage(field string, v interface{}) {
+	if o.messageFields == nil {
+		o.messageFields = map[string]interface{}{}
+	}
+	o.messageFields[field] = v
+}
+
+// transform adds function that changes JSON decoded message before sending
+func (o *sendOptions) transform(fn func(body map[string]interface{}) error) {
+	o.transforms = append(o.transforms, fn)
+}
+
+func (o *sendOptions) fail(err error) {
+	if o.err == nil {
+		o.err = err
+	}
+}
+
+// WithSubscriptionCategory sends message as non promotional subscription message.
+// Category is not sent to Facebook, it is validated and must match category approved for your page
+func WithSubscriptionCategory(c SubscriptionCategory) SendOption {
+	return func(o *sendOptions) {
+		switch c {
+		case SubscriptionCategoryNews, SubscriptionCategoryProductivity, SubscriptionCategoryPersonalTracker:
+			o.set("messaging_type", MessagingTypeSubscription)
+		default:
+			o.fail(fmt.Errorf("%w: %q", ErrInvalidSubscriptionCategory, c))
+		}
+	}
+}
+
+// WithMetadata adds metadata to the message, it is sent back in message echo event. Up to 1000 characters
+func WithMetadata(m string) SendOption {
+	return func(o *sendOptions) {
+		o.setMessage("metadata", m)
+	}
+}
+
+// WithReplyTo sends message as reply to message with message ID mid
+func WithReplyTo(messageID string) SendOption {
+	return func(o *sendOptions) {
+		o.setMessage("reply_to", MessageReference{Mid: messageID})
+	}
+}
+
+// WithRequestDeadline cancels sending if message is not sent within d. Messages sent with deadline
+// are not retried by RetryQueue, use it for time critical messages (e.g. one time passwords) that should fail fast
+func WithRequestDeadline(d time.Duration) SendOption {
+	return func(o *sendOptions) {
+		o.deadline = d
+	}
+}
+
+// WithSendTimeout sets timeout for sending every message, see WithCallTimeout for overriding it per message
+func WithSendTimeout(d time.Duration) Option {
+	return func(msng *Messenger) {
+		msng.SendTimeout = d
+	}
+}
+
+// WithCallTimeout cancels sending if message is not sent within d, overriding messenger's SendTimeout
+// for this message. Use it for messages that legitimately take longer, unlike WithRequestDeadline
+// messages are still retried by RetryQueue
+func WithCallTimeout(d time.Duration) SendOption {
+	return func(o *sendOptions) {
+		o.timeout = d
+	}
+}
+
+// sendDeadline returns deadline set by WithRequestDeadline, 0 if not set
+func sendDeadline(opts []SendOption) time.Duration {
+	return applySendOptions(opts).deadline
+}
+
+// sendTimeout returns timeout for sending message with opts, deadline set by WithRequestDeadline,
+// timeout set by WithCallTimeout or messenger's SendTimeout. 0 if none is set
+func (msng *Messenger) sendTimeout(opts []SendOption) time.Duration {
+	o := applySendOptions(opts)
+	switch {
+	case o.deadline > 0:
+		return o.deadline
+	case o.timeout > 0:
+		return o.timeout
+	}
+	return msng.SendTimeout
+}
+
+func applySendOptions(opts []SendOption) sendOptions {
+	var o sendOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithTag sends message as tagged message, outside of 24 hour window
+func WithTag(tag MessageTag) SendOption {
+	return func(o *sendOptions) {
+		o.set("messaging_type", MessagingTypeMessageTag)
+		o.set("tag", tag)
+	}
+}
+
+// withRecipient sends message to recipientID
+func withRecipient(recipientID string) SendOption {
+	return WithRecipient(PSIDRecipient{ID: recipientID})
+}
+
+// encodeMessage returns JSON of message m with send options applied
+func encodeMessage(m Message, opts []SendOption) ([]byte, error) {
+	s, err := json.Marshal(m)
+	if err != nil || len(opts) == 0 {
+		return s, err
+	}
+
+	var o sendOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.err != nil {
+		return nil, o.err
+	}
+	if len(o.fields) == 0 && len(o.messageFields) == 0 && len(o.transforms) == 0 {
+		return s, nil
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(s, &body); err != nil {
+		return nil, err
+	}
+	for k, v := range o.fields {
+		body[k] = v
+	}
+	if len(o.messageFields) > 0 {
+		msg, _ := body["message"].(map[string]interface{})
+		if msg == nil {
+			msg = map[string]interface{}{}
+		}
+		for k, v := range o.messageFields {
+			msg[k] = v
+		}
+		body["message"] = msg
+	}
+	for _, fn := range o.transforms {
+		if err := fn(body); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(body)
+}
+
+// checkLimits checks JSON encoded message against Messenger Platform size limits
+func checkLimits(s []byte) error {
+	var f struct {
+		Message struct {
+			Metadata   string `json:"metadata"`
+			Attachment struct {
+				Payload struct {
+					ImageAspectRatio ImageAspectRatio `json:"image_aspect_ratio"`
+				} `json:"payload"`
+			} `json:"attachment"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(s, &f); err != nil {
+		return err
+	}
+	if n := utf8.RuneCountInString(f.Message.Metadata); n > maxMetadataLength {
+		return fmt.Errorf("%w: %d characters", ErrMetadataTooLong, n)
+	}
+	return validateAspectRatio(f.Message.Attachment.Payload.ImageAspectRatio)
+}
@@ -0,0 +1,49 @@
+package messenger
+
+import "sync"
+
+// SequenceTracker remembers last message sequence number per user to detect out of order or missing messages
+type SequenceTracker struct {
+	last sync.Map // user ID -> int64
+}
+
+// NewSequenceTracker creates new sequence tracker
+func NewSequenceTracker() *SequenceTracker {
+	return &SequenceTracker{}
+}
+
+// WithSequenceTracker enables detection of out of order messages, see Messenger.OnOutOfOrderEvent
+func WithSequenceTracker(st *SequenceTracker) Option {
+	return func(msng *Messenger) {
+		msng.SequenceTracker = st
+	}
+}
+
+// Track stores seq as the last sequence number for user. If it doesn't follow the previous one
+// expected sequence number is returned with ok set to false. First message of user is always in order.
+// Last sequence number never moves backwards, so retried or reordered events don't break following ones
+func (st *SequenceTracker) Track(userID int64, seq int64) (expected int64, ok bool) {
+	for {
+		prev, loaded := st.last.LoadOrStore(userID, seq)
+		if !loaded {
+			return seq, true
+		}
+		expected = prev.(int64) + 1
+		if seq < expected {
+			return expected, false
+		}
+		if st.last.CompareAndSwap(userID, prev, seq) {
+			return expected, seq == expected
+		}
+	}
+}
+
+// trackSequence tracks message sequence number and fires OnOutOfOrderEvent if sequence is broken
+func (msng *Messenger) trackSequence(userID int64, seq int64) {
+	if msng.SequenceTracker == nil || seq == 0 { // seq is not sent by newer API versions
+		return
+	}
+	if expected, ok := msng.SequenceTracker.Track(userID, seq); !ok && msng.OnOutOfOrderEvent != nil {
+		go msng.OnOutOfOrderEvent(msng, userID, expected, seq)
+	}
+}
@@ -0,0 +1,47 @@
+package messenger_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestSequenceTracker(t *testing.T) {
+	st := messenger.NewSequenceTracker()
+	for _, seq := range []int64{5, 6, 7} {
+		if _, ok := st.Track(1, seq); !ok {
+			t.Error("Sequence", seq, "should be in order")
+		}
+	}
+
+	if expected, ok := st.Track(1, 9); ok || expected != 8 {
+		t.Error("Expected gap with expected 8, returned", expected, ok)
+	}
+	// late event is reported, but doesn't break following events
+	if expected, ok := st.Track(1, 8); ok || expected != 10 {
+		t.Error("Expected late event with expected 10, returned", expected, ok)
+	}
+	if _, ok := st.Track(1, 10); !ok {
+		t.Error("Sequence 10 should be in order after late event")
+	}
+	if _, ok := st.Track(2, 1); !ok {
+		t.Error("First message of other user should be in order")
+	}
+}
+
+func TestSequenceTrackerConcurrent(t *testing.T) {
+	st := messenger.NewSequenceTracker()
+	var wg sync.WaitGroup
+	for seq := int64(1); seq <= 100; seq++ {
+		wg.Add(1)
+		go func(seq int64) {
+			defer wg.Done()
+			st.Track(1, seq)
+		}(seq)
+	}
+	wg.Wait()
+	if _, ok := st.Track(1, 101); !ok {
+		t.Error("Expected the highest sequence number kept")
+	}
+}
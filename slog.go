@@ -0,0 +1,38 @@
+package messenger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogLogger adapts log/slog logger to Logger interface
+//
+//	msng := messenger.New(accessToken, pageID, messenger.WithLogger(messenger.NewSlogLogger(slog.Default())))
+//
+// Sent messages are logged at debug level, errors at error level and received webhook events at info level
+type SlogLogger struct {
+	Logger *slog.Logger
+}
+
+// NewSlogLogger creates new SlogLogger that logs to l, slog.Default() is used if l is nil
+func NewSlogLogger(l *slog.Logger) *SlogLogger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return &SlogLogger{Logger: l}
+}
+
+// Debug logs at slog.LevelDebug
+func (s *SlogLogger) Debug(msg string, keyvals ...interface{}) {
+	s.Logger.Log(context.Background(), slog.LevelDebug, msg, keyvals...)
+}
+
+// Info logs at slog.LevelInfo
+func (s *SlogLogger) Info(msg string, keyvals ...interface{}) {
+	s.Logger.Log(context.Background(), slog.LevelInfo, msg, keyvals...)
+}
+
+// Error logs at slog.LevelError
+func (s *SlogLogger) Error(msg string, keyvals ...interface{}) {
+	s.Logger.Log(context.Background(), slog.LevelError, msg, keyvals...)
+}
@@ -0,0 +1,41 @@
+package messenger_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestSlogLogger(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"recipient_id":"123","message_id":"mid.1"}`))
+	})
+
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	msng := messenger.New("XXXXXXX", "", mock, messenger.WithLogger(messenger.NewSlogLogger(l)))
+	if _, err := msng.SendTextMessageStr(context.Background(), "123", "hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, s := range []string{`level=DEBUG msg="sending message"`, `"text\":\"hello\"`, `level=DEBUG msg="message sent" recipient_id=123`, "response_message_id=mid.1"} {
+		if !strings.Contains(out, s) {
+			t.Error("Expected", s, "logged, logged", out)
+		}
+	}
+
+	buf.Reset()
+	l = slog.New(slog.NewTextHandler(&buf, nil)) // info level
+	msng = messenger.New("XXXXXXX", "", mock, messenger.WithLogger(messenger.NewSlogLogger(l)))
+	msng.SendTextMessageStr(context.Background(), "123", "hello")
+	if buf.Len() != 0 {
+		t.Error("Expected sent messages not logged at info level, logged", buf.String())
+	}
+}
@@ -0,0 +1,135 @@
+package messenger
+
+import (
+	"strconv"
+	"sync"
+)
+
+// ConversationManager stores state of conversation flow and other data per user.
+// Missing state or data is returned as empty string
+type ConversationManager interface {
+	GetState(userID string) (string, error)
+	SetState(userID, state string) error
+	ClearState(userID string) error
+	GetData(userID, key string) (string, error)
+	SetData(userID, key, value string) error
+}
+
+// WithConversationManager sets conversation manager used by bot handlers, see GetConversationManager
+func WithConversationManager(cm ConversationManager) Option {
+	return func(msng *Messenger) {
+		msng.ConversationManager = cm
+	}
+}
+
+// GetConversationManager returns messenger's conversation manager, nil if it is not set
+func (msng *Messenger) GetConversationManager() ConversationManager {
+	return msng.ConversationManager
+}
+
+// MemoryConversationManager is ConversationManager kept in memory
+type MemoryConversationManager struct {
+	states sync.Map // userID -> state
+	data   sync.Map // userID -> *sync.Map of key -> value
+}
+
+// NewMemoryConversationManager creates empty MemoryConversationManager
+func NewMemoryConversationManager() *MemoryConversationManager {
+	return &MemoryConversationManager{}
+}
+
+// GetState returns user's state
+func (cm *MemoryConversationManager) GetState(userID string) (string, error) {
+	s, _ := cm.states.Load(userID)
+	state, _ := s.(string)
+	return state, nil
+}
+
+// SetState sets user's state
+func (cm *MemoryConversationManager) SetState(userID, state string) error {
+	cm.states.Store(userID, state)
+	return nil
+}
+
+// ClearState removes user's state and data
+func (cm *MemoryConversationManager) ClearState(userID string) error {
+	cm.states.Delete(userID)
+	cm.data.Delete(userID)
+	return nil
+}
+
+// GetData returns user's data stored under key
+func (cm *MemoryConversationManager) GetData(userID, key string) (string, error) {
+	d, ok := cm.data.Load(userID)
+	if !ok {
+		return "", nil
+	}
+	v, _ := d.(*sync.Map).Load(key)
+	value, _ := v.(string)
+	return value, nil
+}
+
+// SetData stores user's data under key
+func (cm *MemoryConversationManager) SetData(userID, key, value string) error {
+	d, _ := cm.data.LoadOrStore(userID, &sync.Map{})
+	d.(*sync.Map).Store(key, value)
+	return nil
+}
+
+// FlowHandler handles message received in conversation flow state and returns next state,
+// empty next state ends the flow
+type FlowHandler func(msng *Messenger, userID int64, m FacebookMessage) (next string, err error)
+
+// ConversationFlow routes received messages to handler of user's current state and moves user to next state.
+// States are stored in ConversationManager
+type ConversationFlow struct {
+	cm       ConversationManager
+	initial  string
+	handlers map[string]FlowHandler
+}
+
+// NewConversationFlow creates flow that stores states in cm, users without state start in initial state
+func NewConversationFlow(cm ConversationManager, initial string) *ConversationFlow {
+	return &ConversationFlow{cm: cm, initial: initial, handlers: map[string]FlowHandler{}}
+}
+
+// Handle sets handler for state
+func (f *ConversationFlow) Handle(state string, handler FlowHandler) *ConversationFlow {
+	f.handlers[state] = handler
+	return f
+}
+
+// MessageHandler returns handler that runs the flow, set it as MessageReceived
+func (f *ConversationFlow) MessageHandler() func(msng *Messenger, userID int64, m FacebookMessage) {
+	return func(msng *Messenger, userID int64, m FacebookMessage) {
+		id := strconv.FormatInt(userID, 10)
+		state, err := f.cm.GetState(id)
+		if err != nil {
+			msng.logger().Error("get conversation state failed", "user_id", userID, "error", err)
+			return
+		}
+		if state == "" {
+			state = f.initial
+		}
+
+		h, ok := f.handlers[state]
+		if !ok {
+			msng.logger().Error("no handler for conversation state", "user_id", userID, "state", state)
+			return
+		}
+		next, err := h(msng, userID, m)
+		if err != nil {
+			msng.logger().Error("conversation flow handler failed", "user_id", userID, "state", state, "error", err)
+			return
+		}
+
+		if next == "" {
+			err = f.cm.ClearState(id)
+		} else {
+			err = f.cm.SetState(id, next)
+		}
+		if err != nil {
+			msng.logger().Error("set conversation state failed", "user_id", userID, "error", err)
+		}
+	}
+}
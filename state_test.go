@@ -0,0 +1,42 @@
+package messenger_test
+
+import (
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestConversationFlow(t *testing.T) {
+	cm := messenger.NewMemoryConversationManager()
+	msng := messenger.New("XXXXXXX", "", messenger.WithConversationManager(cm))
+
+	flow := messenger.NewConversationFlow(msng.GetConversationManager(), "ask_name").
+		Handle("ask_name", func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) (string, error) {
+			return "name", nil
+		}).
+		Handle("name", func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) (string, error) {
+			msng.GetConversationManager().SetData("7", "name", m.Text)
+			return "ask_age", nil
+		}).
+		Handle("ask_age", func(msng *messenger.Messenger, userID int64, m messenger.FacebookMessage) (string, error) {
+			return "", nil // done
+		})
+	h := flow.MessageHandler()
+
+	expected := []string{"name", "ask_age", ""}
+	for i, text := range []string{"hi", "Ana", "30"} {
+		h(&msng, 7, messenger.FacebookMessage{Text: text})
+		if state, _ := cm.GetState("7"); state != expected[i] {
+			t.Errorf("After %q expected state %q, got %q", text, expected[i], state)
+		}
+		if i == 1 {
+			if name, _ := cm.GetData("7", "name"); name != "Ana" {
+				t.Error("Expected stored name, got", name)
+			}
+		}
+	}
+
+	if name, _ := cm.GetData("7", "name"); name != "" {
+		t.Error("Expected data cleared when flow ended, got", name)
+	}
+}
@@ -0,0 +1,181 @@
+package messenger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Webhook fields that app can subscribe to, see SubscribeAppToPage
+const (
+	WebhookFieldMessages                   = "messages"
+	WebhookFieldMessagingPostbacks         = "messaging_postbacks"
+	WebhookFieldMessagingOptins            = "messaging_optins"
+	WebhookFieldMessageDeliveries          = "message_deliveries"
+	WebhookFieldMessageReads               = "message_reads"
+	WebhookFieldMessagingHandovers         = "messaging_handovers"
+	WebhookFieldMessagingReferrals         = "messaging_referrals"
+	WebhookFieldMessagingAccountLinking    = "messaging_account_linking"
+	WebhookFieldMessagingPolicyEnforcement = "messaging_policy_enforcement"
+	WebhookFieldFeed                       = "feed"
+)
+
+// Permissions required for webhook subscriptions, see RequiredScopesForFields
+const (
+	ScopePagesMessaging      = "pages_messaging"
+	ScopePagesManageMetadata = "pages_manage_metadata"
+	ScopePagesReadEngagement = "pages_read_engagement"
+)
+
+// fieldScopes maps webhook fields to permissions required besides pages_manage_metadata
+var fieldScopes = map[string][]string{
+	WebhookFieldMessages:                   {ScopePagesMessaging},
+	WebhookFieldMessagingPostbacks:         {ScopePagesMessaging},
+	WebhookFieldMessagingOptins:            {ScopePagesMessaging},
+	WebhookFieldMessageDeliveries:          {ScopePagesMessaging},
+	WebhookFieldMessageReads:               {ScopePagesMessaging},
+	WebhookFieldMessagingHandovers:         {ScopePagesMessaging},
+	WebhookFieldMessagingReferrals:         {ScopePagesMessaging},
+	WebhookFieldMessagingAccountLinking:    {ScopePagesMessaging},
+	WebhookFieldMessagingPolicyEnforcement: {ScopePagesMessaging},
+	WebhookFieldFeed:                       {ScopePagesReadEngagement},
+}
+
+// RequiredScopesForFields returns permissions page access token needs to subscribe to webhook fields
+func RequiredScopesForFields(fields []string) []string {
+	scopes := []string{ScopePagesManageMetadata}
+	seen := map[string]bool{ScopePagesManageMetadata: true}
+	for _, f := range fields {
+		for _, scope := range fieldScopes[f] {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// ValidateTokenScopes returns required scopes that are not granted to messenger's access token, see DebugToken
+func (msng *Messenger) ValidateTokenScopes(ctx context.Context, requiredScopes []string) ([]string, error) {
+	token, err := msng.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, err := msng.DebugToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsValid {
+		return nil, ErrInvalidToken
+	}
+
+	granted := map[string]bool{}
+	for _, scope := range info.Scopes {
+		granted[scope] = true
+	}
+	var missing []string
+	for _, scope := range requiredScopes {
+		if !granted[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	return missing, nil
+}
+
+// SubscribeAppToPage subscribes messenger's app to webhook fields of page with pageID
+func (msng *Messenger) SubscribeAppToPage(ctx context.Context, pageID string, subscribedFields []string) error {
+	body := map[string]interface{}{"subscribed_fields": subscribedFields}
+	return msng.graphRequest(ctx, http.MethodPost, pageID+"/subscribed_apps", nil, body, nil)
+}
+
+// UnsubscribeAppFromPage removes messenger's app webhook subscription from page with pageID
+func (msng *Messenger) UnsubscribeAppFromPage(ctx context.Context, pageID string) error {
+	return msng.graphRequest(ctx, http.MethodDelete, pageID+"/subscribed_apps", nil, nil, nil)
+}
+
+// GetPageWebhookSubscriptions returns webhook fields messenger's app is subscribed to on page with pageID
+func (msng *Messenger) GetPageWebhookSubscriptions(ctx context.Context, pageID string) ([]string, error) {
+	info, err := msng.cachedPageInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data []struct {
+			ID               string   `json:"id"`
+			SubscribedFields []string `json:"subscribed_fields"`
+		} `json:"data"`
+	}
+	if err := msng.graphRequest(ctx, http.MethodGet, pageID+"/subscribed_apps", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	for _, app := range resp.Data {
+		if app.ID == info.AppID {
+			return app.SubscribedFields, nil
+		}
+	}
+	return nil, nil
+}
+
+// WatchSubscription checks every interval that messenger's page is subscribed to webhook fields and
+// subscribes it again if any of them is missing, e.g. after page token is rotated. Watching stops when
+// ctx is cancelled. First check is done before returning and its error is returned, later errors are
+// passed to OnSubscriptionError. Interval must be greater than zero
+func (msng *Messenger) WatchSubscription(ctx context.Context, interval time.Duration, fields []string) error {
+	if interval <= 0 {
+		return fmt.Errorf("messenger: invalid subscription check interval %s", interval)
+	}
+	if err := msng.ensureSubscription(ctx, fields); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := msng.ensureSubscription(ctx, fields); err != nil && ctx.Err() == nil {
+					msng.logger().Error("webhook subscription check failed", "error", err)
+					if msng.OnSubscriptionError != nil {
+						msng.OnSubscriptionError(err)
+					}
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// ensureSubscription subscribes page to fields it is not subscribed to, keeping existing subscriptions
+func (msng *Messenger) ensureSubscription(ctx context.Context, fields []string) error {
+	info, err := msng.cachedPageInfo(ctx)
+	if err != nil {
+		return err
+	}
+	subscribed, err := msng.GetPageWebhookSubscriptions(ctx, info.ID)
+	if err != nil {
+		return err
+	}
+
+	has := map[string]bool{}
+	for _, f := range subscribed {
+		has[f] = true
+	}
+	missing := false
+	for _, f := range fields {
+		if !has[f] {
+			has[f] = true
+			subscribed = append(subscribed, f)
+			missing = true
+		}
+	}
+	if !missing {
+		return nil
+	}
+	msng.logger().Info("webhook subscription missing fields, subscribing again", "page_id", info.ID)
+	return msng.SubscribeAppToPage(ctx, info.ID, subscribed)
+}
@@ -0,0 +1,159 @@
+package messenger_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestPageSubscriptions(t *testing.T) {
+	t.Parallel()
+	var subscribed []string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/me":
+			w.Write([]byte(`{"id":"PAGE_ID","name":"Page"}`))
+		case r.URL.Path == "/app":
+			w.Write([]byte(`{"id":"APP_ID"}`))
+		case r.URL.Path != "/PAGE_ID/subscribed_apps":
+			t.Error("Unexpected path", r.URL.Path)
+		case r.Method == http.MethodPost:
+			var body struct {
+				SubscribedFields []string `json:"subscribed_fields"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			subscribed = body.SubscribedFields
+			w.Write([]byte(`{"success":true}`))
+		case r.Method == http.MethodDelete:
+			subscribed = nil
+			w.Write([]byte(`{"success":true}`))
+		default:
+			b, _ := json.Marshal(subscribed)
+			w.Write([]byte(`{"data":[{"id":"OTHER_APP","subscribed_fields":["feed"]},{"id":"APP_ID","subscribed_fields":` + string(b) + `}]}`))
+		}
+	})
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "", mock)
+	fields := []string{messenger.WebhookFieldMessages, messenger.WebhookFieldMessagingPostbacks}
+	if err := msng.SubscribeAppToPage(ctx, "PAGE_ID", fields); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := msng.GetPageWebhookSubscriptions(ctx, "PAGE_ID")
+	if err != nil || !reflect.DeepEqual(got, fields) {
+		t.Error("Expected", fields, "returned", got, err)
+	}
+
+	if err := msng.UnsubscribeAppFromPage(ctx, "PAGE_ID"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := msng.GetPageWebhookSubscriptions(ctx, "PAGE_ID"); err != nil || len(got) != 0 {
+		t.Error("Expected no subscriptions, returned", got, err)
+	}
+}
+
+func TestValidateTokenScopes(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/me":
+			w.Write([]byte(`{"id":"PAGE_ID","name":"Page"}`))
+		case "/app":
+			w.Write([]byte(`{"id":"APP_ID"}`))
+		case "/debug_token":
+			if r.FormValue("input_token") != "XXXXXXX" || r.FormValue("access_token") != "APP_ID|SECRET" {
+				t.Error("Unexpected debug token query", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"data":{"app_id":"APP_ID","is_valid":true,"scopes":["pages_messaging","pages_manage_metadata"]}}`))
+		default:
+			t.Error("Unexpected path", r.URL.Path)
+		}
+	})
+
+	required := messenger.RequiredScopesForFields([]string{messenger.WebhookFieldMessages, messenger.WebhookFieldFeed})
+	if !reflect.DeepEqual(required, []string{"pages_manage_metadata", "pages_messaging", "pages_read_engagement"}) {
+		t.Error("Unexpected required scopes", required)
+	}
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	msng.AppSecret = "SECRET"
+	missing, err := msng.ValidateTokenScopes(context.Background(), required)
+	if err != nil || !reflect.DeepEqual(missing, []string{"pages_read_engagement"}) {
+		t.Error("Expected missing pages_read_engagement, returned", missing, err)
+	}
+}
+
+func TestWatchSubscription(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	subscribed := []string{messenger.WebhookFieldFeed}
+	failSubscribe := false
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/me":
+			w.Write([]byte(`{"id":"PAGE_ID","name":"Page"}`))
+		case r.URL.Path == "/app":
+			w.Write([]byte(`{"id":"APP_ID"}`))
+		case r.Method == http.MethodPost && failSubscribe:
+			w.Write([]byte(`{"error":{"message":"Permissions error","type":"OAuthException","code":200}}`))
+		case r.Method == http.MethodPost:
+			var body struct {
+				SubscribedFields []string `json:"subscribed_fields"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			subscribed = body.SubscribedFields
+			w.Write([]byte(`{"success":true}`))
+		default:
+			b, _ := json.Marshal(subscribed)
+			w.Write([]byte(`{"data":[{"id":"APP_ID","subscribed_fields":` + string(b) + `}]}`))
+		}
+	})
+
+	errs := make(chan error, 10)
+	msng := messenger.New("XXXXXXX", "", mock)
+	msng.OnSubscriptionError = func(err error) { errs <- err }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fields := []string{messenger.WebhookFieldMessages}
+	if err := msng.WatchSubscription(ctx, 10*time.Millisecond, fields); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	expected := []string{messenger.WebhookFieldFeed, messenger.WebhookFieldMessages}
+	if !reflect.DeepEqual(subscribed, expected) {
+		t.Error("Expected", expected, "subscribed", subscribed)
+	}
+	// subscription revoked, subscribing again fails
+	subscribed, failSubscribe = nil, true
+	mu.Unlock()
+
+	select {
+	case err := <-errs:
+		var apiErr messenger.FacebookAPIError
+		if !errors.As(err, &apiErr) || apiErr.Code != 200 {
+			t.Error("Unexpected error", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected OnSubscriptionError call")
+	}
+}
+
+func TestWatchSubscriptionInvalidInterval(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+	if err := msng.WatchSubscription(context.Background(), 0, []string{messenger.WebhookFieldMessages}); err == nil {
+		t.Error("Expected error for zero interval")
+	}
+}
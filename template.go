@@ -0,0 +1,302 @@
+package messenger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// Template types of templates sent with TemplateMessage
+const (
+	// TemplateTypeList for list templates
+	TemplateTypeList = TemplateType("list")
+
+	// TemplateTypeOpenGraph for open graph templates
+	TemplateTypeOpenGraph = TemplateType("open_graph")
+
+	// TemplateTypeAirlineItinerary for airline itinerary templates
+	TemplateTypeAirlineItinerary = TemplateType("airline_itinerary")
+)
+
+// templateElement is implemented by element types of TemplateMessage
+type templateElement interface {
+	templateType() TemplateType
+	validateElement() error
+	previewLines(n int) []string // preview of n-th element for RenderMessagePreview
+}
+
+// GenericElement is element of generic template, same as Element
+type GenericElement = Element
+
+// ListElement is element of list template
+type ListElement struct {
+	Title         string         `json:"title"`
+	Subtitle      string         `json:"subtitle,omitempty"`
+	ImageURL      string         `json:"image_url,omitempty"`
+	DefaultAction *DefaultAction `json:"default_action,omitempty"`
+	Buttons       []Button       `json:"buttons,omitempty"` // up to 1 button
+}
+
+// OpenGraphElement is element of open graph template, URL is link to open graph object like song
+type OpenGraphElement struct {
+	URL     string   `json:"url"`
+	Buttons []Button `json:"buttons,omitempty"`
+}
+
+// AirportInfo is airport of AirlineFlightSegment
+type AirportInfo struct {
+	AirportCode string `json:"airport_code"`
+	City        string `json:"city"`
+	Terminal    string `json:"terminal,omitempty"`
+	Gate        string `json:"gate,omitempty"`
+}
+
+// FlightSchedule of AirlineFlightSegment, times are in ISO 8601 format like 2016-01-02T19:45
+type FlightSchedule struct {
+	BoardingTime  string `json:"boarding_time,omitempty"`
+	DepartureTime string `json:"departure_time"`
+	ArrivalTime   string `json:"arrival_time,omitempty"`
+}
+
+// AirlineFlightSegment is flight of airline itinerary template, sent as flight_info
+type AirlineFlightSegment struct {
+	ConnectionID     string         `json:"connection_id"`
+	SegmentID        string         `json:"segment_id"`
+	FlightNumber     string         `json:"flight_number"`
+	AircraftType     string         `json:"aircraft_type,omitempty"`
+	DepartureAirport AirportInfo    `json:"departure_airport"`
+	ArrivalAirport   AirportInfo    `json:"arrival_airport"`
+	FlightSchedule   FlightSchedule `json:"flight_schedule"`
+	TravelClass      string         `json:"travel_class"` // economy, business or first_class
+}
+
+func (Element) templateType() TemplateType              { return TemplateTypeGeneric }
+func (ListElement) templateType() TemplateType          { return TemplateTypeList }
+func (OpenGraphElement) templateType() TemplateType     { return TemplateTypeOpenGraph }
+func (AirlineFlightSegment) templateType() TemplateType { return TemplateTypeAirlineItinerary }
+
+// elementsKey returns payload field airline itinerary segments are sent in
+func (AirlineFlightSegment) elementsKey() string { return "flight_info" }
+
+// validateElement checks that generic template element has title and up to 3 buttons
+func (e Element) validateElement() error {
+	if e.Title == "" {
+		return fmt.Errorf("%w: title", ErrElementFieldMissing)
+	}
+	if len(e.Buttons) > maxElementButtons {
+		return fmt.Errorf("%w: element %q has %d buttons", ErrMaxButtons, e.Title, len(e.Buttons))
+	}
+	return e.validate()
+}
+
+// validateElement checks that list template element has title and up to 1 button
+func (e ListElement) validateElement() error {
+	if e.Title == "" {
+		return fmt.Errorf("%w: title", ErrElementFieldMissing)
+	}
+	if len(e.Buttons) > 1 {
+		return fmt.Errorf("%w: list element %q has %d buttons", ErrMaxButtons, e.Title, len(e.Buttons))
+	}
+	if n := utf8.RuneCountInString(e.Title); n > maxElementTitleLength {
+		return fmt.Errorf("%w: %d characters", ErrTitleTooLong, n)
+	}
+	if n := utf8.RuneCountInString(e.Subtitle); n > maxElementSubtitleLength {
+		return fmt.Errorf("%w: %d characters", ErrSubtitleTooLong, n)
+	}
+	if e.DefaultAction != nil {
+		return e.DefaultAction.Validate()
+	}
+	return nil
+}
+
+// validateElement checks that open graph element has URL and up to 3 buttons
+func (e OpenGraphElement) validateElement() error {
+	if e.URL == "" {
+		return fmt.Errorf("%w: url", ErrElementFieldMissing)
+	}
+	if len(e.Buttons) > maxElementButtons {
+		return fmt.Errorf("%w: element %q has %d buttons", ErrMaxButtons, e.URL, len(e.Buttons))
+	}
+	return nil
+}
+
+// validateElement checks that flight segment has flight number and airports
+func (s AirlineFlightSegment) validateElement() error {
+	switch {
+	case s.FlightNumber == "":
+		return fmt.Errorf("%w: flight_number", ErrElementFieldMissing)
+	case s.DepartureAirport.AirportCode == "":
+		return fmt.Errorf("%w: departure_airport", ErrElementFieldMissing)
+	case s.ArrivalAirport.AirportCode == "":
+		return fmt.Errorf("%w: arrival_airport", ErrElementFieldMissing)
+	}
+	return nil
+}
+
+func (e Element) previewLines(n int) []string {
+	return elementPreview(fmt.Sprintf("Card %d: %s", n, e.Title), e.Subtitle, e.Buttons)
+}
+
+func (e ListElement) previewLines(n int) []string {
+	return elementPreview(fmt.Sprintf("Item %d: %s", n, e.Title), e.Subtitle, e.Buttons)
+}
+
+func (e OpenGraphElement) previewLines(n int) []string {
+	return elementPreview(fmt.Sprintf("Link %d: %s", n, e.URL), "", e.Buttons)
+}
+
+func (s AirlineFlightSegment) previewLines(n int) []string {
+	return []string{fmt.Sprintf("Flight %d: %s %s -> %s", n, s.FlightNumber, s.DepartureAirport.AirportCode, s.ArrivalAirport.AirportCode)}
+}
+
+// TemplateElement wraps type specific element data of TemplateMessage, it is encoded as Data
+type TemplateElement[T any] struct {
+	Data T
+}
+
+// MarshalJSON encodes element data
+func (e TemplateElement[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Data)
+}
+
+// UnmarshalJSON decodes element data
+func (e *TemplateElement[T]) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &e.Data)
+}
+
+// TemplateMessage is template message with elements of type T, e.g. TemplateMessage[ListElement].
+// Create it with NewTemplateMessage which sets TemplateType for element type
+type TemplateMessage[T any] struct {
+	Recipient    recipient
+	TemplateType string
+	Elements     []TemplateElement[T]
+
+	// Payload holds other template payload fields, e.g. pnr_number and passenger_info of airline itinerary
+	Payload map[string]interface{}
+}
+
+func (m TemplateMessage[T]) foo() {} // Message interface
+
+// NewTemplateMessage creates template message for recipientID with elements, template type is set by element type.
+// Elements are validated like with AddElement
+func NewTemplateMessage[T templateElement](recipientID string, elements ...T) (TemplateMessage[T], error) {
+	var zero T
+	m := TemplateMessage[T]{
+		Recipient:    newRecipient(recipientID),
+		TemplateType: string(zero.templateType()),
+	}
+	for _, e := range elements {
+		if err := m.AddElement(e); err != nil {
+			return TemplateMessage[T]{}, err
+		}
+	}
+	return m, nil
+}
+
+// AddElement validates element e and adds it to template message. Up to 10 elements can be added, elements must
+// have required fields (e.g. title) and allowed number of buttons (3, or 1 for list elements)
+func (m *TemplateMessage[T]) AddElement(e T) error {
+	if len(m.Elements) >= maxElements {
+		return fmt.Errorf("%w: %d elements", ErrMaxElements, len(m.Elements)+1)
+	}
+	if v, ok := interface{}(e).(templateElement); ok {
+		if err := v.validateElement(); err != nil {
+			return err
+		}
+	}
+	m.Elements = append(m.Elements, TemplateElement[T]{Data: e})
+	return nil
+}
+
+// templateElementsKey returns payload field elements of type T are sent in
+func templateElementsKey[T any]() string {
+	var zero T
+	if k, ok := interface{}(zero).(interface{ elementsKey() string }); ok {
+		return k.elementsKey()
+	}
+	return "elements"
+}
+
+// MarshalJSON encodes message in Send API format
+func (m TemplateMessage[T]) MarshalJSON() ([]byte, error) {
+	p := map[string]interface{}{}
+	for k, v := range m.Payload {
+		p[k] = v
+	}
+	p["template_type"] = m.TemplateType
+
+	p[templateElementsKey[T]()] = m.Elements
+
+	return json.Marshal(map[string]interface{}{
+		"recipient": m.Recipient,
+		"message": map[string]interface{}{
+			"attachment": map[string]interface{}{
+				"type":    AttachmentTypeTemplate,
+				"payload": p,
+			},
+		},
+	})
+}
+
+// messageInfo returns recipient and template type of message for logging
+func (m TemplateMessage[T]) messageInfo() (recipientID string, messageType string) {
+	return m.Recipient.ID, m.TemplateType
+}
+
+// UnmarshalJSON decodes message encoded in Send API format, used for restoring messages from MessageEnvelope
+func (m *TemplateMessage[T]) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Recipient recipient `json:"recipient"`
+		Message   struct {
+			Attachment struct {
+				Payload map[string]json.RawMessage `json:"payload"`
+			} `json:"attachment"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*m = TemplateMessage[T]{Recipient: raw.Recipient}
+	for k, v := range raw.Message.Attachment.Payload {
+		var err error
+		switch k {
+		case "template_type":
+			err = json.Unmarshal(v, &m.TemplateType)
+		case templateElementsKey[T]():
+			err = json.Unmarshal(v, &m.Elements)
+		default:
+			if m.Payload == nil {
+				m.Payload = map[string]interface{}{}
+			}
+			var value interface{}
+			err = json.Unmarshal(v, &value)
+			m.Payload[k] = value
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envelopeType returns name message is stored with in MessageEnvelope, e.g. TemplateMessage[ListElement]
+func (m TemplateMessage[T]) envelopeType() string {
+	return "TemplateMessage[" + reflect.TypeOf((*T)(nil)).Elem().Name() + "]"
+}
+
+// renderPreview returns preview of message for RenderMessagePreview
+func (m TemplateMessage[T]) renderPreview() string {
+	var lines []string
+	for i, e := range m.Elements {
+		if p, ok := interface{}(e.Data).(templateElement); ok {
+			lines = append(lines, p.previewLines(i+1)...)
+		}
+	}
+	if len(lines) == 0 {
+		return "Template: " + m.TemplateType
+	}
+	return strings.Join(lines, "\n")
+}
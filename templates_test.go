@@ -0,0 +1,241 @@
+package messenger_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestGenericAspectRatio(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+
+	m := msng.NewGenericMessage(123, messenger.WithAspectRatio(messenger.AspectRatioSquare))
+	b, _ := json.Marshal(m)
+	if !strings.Contains(string(b), `"image_aspect_ratio":"square"`) {
+		t.Error("Expected square aspect ratio, encoded", string(b))
+	}
+
+	m = msng.NewGenericMessage(123)
+	b, _ = json.Marshal(m)
+	if strings.Contains(string(b), "image_aspect_ratio") {
+		t.Error("Expected no aspect ratio, encoded", string(b))
+	}
+
+	if err := m.SetAspectRatio("wide"); !errors.Is(err, messenger.ErrInvalidAspectRatio) {
+		t.Error("Expected ErrInvalidAspectRatio, returned", err)
+	}
+	if err := m.SetAspectRatio(messenger.AspectRatioHorizontal); err != nil {
+		t.Error(err)
+	}
+
+	m = msng.NewGenericMessage(123, messenger.WithAspectRatio("wide"))
+	if _, err := msng.SendMessage(m); !errors.Is(err, messenger.ErrInvalidAspectRatio) {
+		t.Error("Expected ErrInvalidAspectRatio on send, returned", err)
+	}
+}
+
+func TestTemplateSharable(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+	buttons := []messenger.Button{msng.NewWebURLButton("Open", "https://example.com")}
+
+	tests := []struct {
+		m        messenger.Message
+		sharable bool
+	}{
+		{msng.NewGenericMessage(123), false},
+		{msng.NewGenericMessage(123, messenger.WithSharable(false)), false},
+		{msng.NewGenericMessage(123, messenger.WithSharable(true)), true},
+		{msng.NewButtonMessage(123, "Text", buttons), false},
+		{msng.NewButtonMessage(123, "Text", buttons, messenger.WithSharable(true)), true},
+	}
+	for _, test := range tests {
+		b, _ := json.Marshal(test.m)
+		if strings.Contains(string(b), `"sharable":true`) != test.sharable {
+			t.Errorf("Expected sharable %v, encoded %s", test.sharable, b)
+		}
+		if strings.Contains(string(b), `"sharable":false`) {
+			t.Error("Expected sharable to be omitted, encoded", string(b))
+		}
+	}
+
+	b, _ := json.Marshal(msng.NewButtonMessage(123, "Text", buttons))
+	if !strings.Contains(string(b), `"template_type":"button","text":"Text"`) {
+		t.Error("Unexpected button template", string(b))
+	}
+}
+
+func TestDefaultAction(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+	el := msng.NewElement("Title", "", "", "", nil)
+
+	a := messenger.NewURLDefaultAction("https://example.com/item")
+	a.MessengerExtensions = true
+	if err := el.WithDefaultAction(a); !errors.Is(err, messenger.ErrFallbackURLRequired) {
+		t.Error("Expected ErrFallbackURLRequired, returned", err)
+	}
+	if el.DefaultAction != nil {
+		t.Error("Expected invalid default action not to be set")
+	}
+
+	a.FallbackURL = "https://example.com"
+	a.WebviewHeightRatio = messenger.WebviewHeightRatioTall
+	if err := el.WithDefaultAction(a); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := json.Marshal(el)
+	expected := `"default_action":{"type":"web_url","url":"https://example.com/item","webview_height_ratio":"tall","messenger_extensions":true,"fallback_url":"https://example.com"}`
+	if !strings.Contains(string(b), expected) {
+		t.Error("Expected", expected, "encoded", string(b))
+	}
+}
+
+func TestGenericElementDefaultAction(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+	m := msng.NewGenericMessage(123)
+
+	a := messenger.NewURLDefaultAction("https://example.com/app")
+	a.MessengerExtensions = true
+	el := messenger.Element{Title: "Card", DefaultAction: &a}
+	if err := m.AddElement(el); !errors.Is(err, messenger.ErrFallbackURLRequired) {
+		t.Error("Expected ErrFallbackURLRequired, returned", err)
+	}
+
+	a.FallbackURL = "https://example.com"
+	if err := m.AddElement(el); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(m)
+	if !strings.Contains(string(b), `"elements":[{"title":"Card","default_action":{"type":"web_url","url":"https://example.com/app","messenger_extensions":true,"fallback_url":"https://example.com"}}]`) {
+		t.Error("Unexpected generic template", string(b))
+	}
+}
+
+func TestElementLimits(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+	m := msng.NewGenericMessage(123)
+	long := strings.Repeat("ž", 81)
+
+	if err := m.AddNewElement(long, "", "", "", nil); !errors.Is(err, messenger.ErrTitleTooLong) {
+		t.Error("Expected ErrTitleTooLong, returned", err)
+	}
+	if err := m.AddNewElement("Title", long, "", "", nil); !errors.Is(err, messenger.ErrSubtitleTooLong) {
+		t.Error("Expected ErrSubtitleTooLong, returned", err)
+	}
+	if err := m.AddNewElement(strings.Repeat("ž", 80), strings.Repeat("ž", 80), "", "", nil); err != nil {
+		t.Error(err)
+	}
+
+	el := messenger.TruncateElement(msng.NewElement(long, "Subtitle", "", "", nil))
+	if el.Title != strings.Repeat("ž", 79)+"…" || el.Subtitle != "Subtitle" {
+		t.Error("Unexpected truncated element", el.Title, el.Subtitle)
+	}
+	if err := m.AddElement(el); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTemplateMessage(t *testing.T) {
+	m, err := messenger.NewTemplateMessage("42", messenger.GenericElement{Title: "Card"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"message":{"attachment":{"payload":{"elements":[{"title":"Card"}],"template_type":"generic"},"type":"template"}},"recipient":{"id":"42"}}`
+	if string(b) != expected {
+		t.Error("Expected", expected, "encoded", string(b))
+	}
+
+	list, _ := messenger.NewTemplateMessage[messenger.ListElement]("42")
+	if err := list.AddElement(messenger.ListElement{Title: "Item"}); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := json.Marshal(list); !strings.Contains(string(b), `"elements":[{"title":"Item"}],"template_type":"list"`) {
+		t.Error("Unexpected list template", string(b))
+	}
+
+	airline, err := messenger.NewTemplateMessage("42", messenger.AirlineFlightSegment{
+		FlightNumber:     "KL9123",
+		DepartureAirport: messenger.AirportInfo{AirportCode: "SFO", City: "San Francisco"},
+		ArrivalAirport:   messenger.AirportInfo{AirportCode: "SLC", City: "Salt Lake City"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	airline.Payload = map[string]interface{}{"pnr_number": "ABCDEF"}
+	b, _ = json.Marshal(airline)
+	if !strings.Contains(string(b), `"flight_info":[{`) || !strings.Contains(string(b), `"pnr_number":"ABCDEF","template_type":"airline_itinerary"`) {
+		t.Error("Unexpected airline template", string(b))
+	}
+}
+
+func TestSendTemplateMessage(t *testing.T) {
+	fb.Reset()
+
+	msng := messenger.New("XXXXXXX", "")
+	m, _ := messenger.NewTemplateMessage("42", messenger.OpenGraphElement{URL: "https://open.spotify.com/track/1"})
+	if _, err := msng.SendMessage(&m); err != nil {
+		t.Fatal(err)
+	}
+	call, _ := fb.LastCall()
+	if !strings.Contains(string(call.Body), `"template_type":"open_graph"`) || !strings.Contains(string(call.Body), `"recipient":{"id":"42"}`) {
+		t.Error("Unexpected request", string(call.Body))
+	}
+}
+
+func TestTemplateMessageValidation(t *testing.T) {
+	if _, err := messenger.NewTemplateMessage("42", messenger.GenericElement{Subtitle: "No title"}); !errors.Is(err, messenger.ErrElementFieldMissing) {
+		t.Error("Expected ErrElementFieldMissing, returned", err)
+	}
+
+	list, _ := messenger.NewTemplateMessage[messenger.ListElement]("42")
+	buttons := []messenger.Button{{Type: messenger.ButtonTypePostback, Title: "Buy", Payload: "BUY"}, {Type: messenger.ButtonTypePostback, Title: "Share", Payload: "SHARE"}}
+	if err := list.AddElement(messenger.ListElement{Title: "Item", Buttons: buttons}); !errors.Is(err, messenger.ErrMaxButtons) {
+		t.Error("Expected ErrMaxButtons, returned", err)
+	}
+
+	og, _ := messenger.NewTemplateMessage[messenger.OpenGraphElement]("42")
+	for i := 0; i < 10; i++ {
+		if err := og.AddElement(messenger.OpenGraphElement{URL: "https://open.spotify.com/track/1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := og.AddElement(messenger.OpenGraphElement{URL: "https://open.spotify.com/track/1"}); !errors.Is(err, messenger.ErrMaxElements) {
+		t.Error("Expected ErrMaxElements, returned", err)
+	}
+}
+
+func TestTemplateMessageEnvelopeAndPreview(t *testing.T) {
+	list, err := messenger.NewTemplateMessage("42",
+		messenger.ListElement{Title: "Classic T-Shirt", Subtitle: "100% Cotton", Buttons: []messenger.Button{{Type: messenger.ButtonTypePostback, Title: "Buy", Payload: "BUY"}}},
+		messenger.ListElement{Title: "Classic Hoodie"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.Payload = map[string]interface{}{"top_element_style": "compact"}
+
+	env, err := messenger.WrapMessage(&list)
+	if err != nil || env.Type != "TemplateMessage[ListElement]" {
+		t.Fatal("Unexpected envelope", env.Type, err)
+	}
+	m, err := env.Unwrap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored, ok := m.(*messenger.TemplateMessage[messenger.ListElement]); !ok || !messenger.EqualMessages(restored, list) {
+		t.Errorf("Expected %+v, restored %+v", list, m)
+	}
+
+	preview, err := messenger.RenderMessagePreview(m)
+	expected := "Item 1: Classic T-Shirt\n  100% Cotton\n  [Buy]\nItem 2: Classic Hoodie"
+	if err != nil || preview != expected {
+		t.Errorf("Expected %q, rendered %q %v", expected, preview, err)
+	}
+}
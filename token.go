@@ -0,0 +1,178 @@
+package messenger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// AccessTokenProvider provides page access token for every API call, use it for tokens that are refreshed periodically
+type AccessTokenProvider interface {
+	GetAccessToken(ctx context.Context) (string, error)
+}
+
+// WithAccessTokenProvider sets provider used instead of AccessToken
+func WithAccessTokenProvider(p AccessTokenProvider) Option {
+	return func(msng *Messenger) {
+		msng.TokenProvider = p
+	}
+}
+
+// accessToken returns token from TokenProvider if set, otherwise AccessToken.
+// Provider errors are wrapped with ErrTokenRefresh
+func (msng *Messenger) accessToken(ctx context.Context) (string, error) {
+	if msng.TokenProvider == nil {
+		return msng.AccessToken, nil
+	}
+	token, err := msng.TokenProvider.GetAccessToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrTokenRefresh, err)
+	}
+	return token, nil
+}
+
+type staticTokenProvider string
+
+func (p staticTokenProvider) GetAccessToken(ctx context.Context) (string, error) {
+	return string(p), nil
+}
+
+// StaticTokenProvider always provides the same token
+func StaticTokenProvider(token string) AccessTokenProvider {
+	return staticTokenProvider(token)
+}
+
+type longLivedTokenProvider struct {
+	mu              sync.Mutex
+	shortLivedToken string
+	appID           string
+	appSecret       string
+	token           string
+
+	client  *http.Client
+	baseURL string // default Graph API URL if empty
+}
+
+// LongLivedTokenProvider exchanges short lived token for long lived token on first use and caches it.
+// If exchange fails, it is retried on next API call
+func LongLivedTokenProvider(shortLivedToken, appID, appSecret string) AccessTokenProvider {
+	return &longLivedTokenProvider{
+		shortLivedToken: shortLivedToken,
+		appID:           appID,
+		appSecret:       appSecret,
+		client:          http.DefaultClient,
+	}
+}
+
+// LongLivedTokenProvider returns LongLivedTokenProvider for messenger's app, token is exchanged
+// with messenger's HTTP client and API version
+func (msng *Messenger) LongLivedTokenProvider(shortLivedToken string) AccessTokenProvider {
+	return &longLivedTokenProvider{
+		shortLivedToken: shortLivedToken,
+		appID:           msng.AppID,
+		appSecret:       msng.AppSecret,
+		client:          msng.GetClient(),
+		baseURL:         msng.baseURL(),
+	}
+}
+
+func (p *longLivedTokenProvider) GetAccessToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != "" {
+		return p.token, nil
+	}
+
+	baseURL := p.baseURL
+	if baseURL == "" {
+		baseURL = graphBaseURL()
+	}
+	t, err := exchangeToken(ctx, p.client, baseURL, p.appID, p.appSecret, p.shortLivedToken)
+	if err != nil {
+		return "", err
+	}
+	p.token = t.AccessToken
+	return p.token, nil
+}
+
+// exchangeToken exchanges short lived user token for long lived one at Graph API with baseURL
+func exchangeToken(ctx context.Context, c *http.Client, baseURL, appID, appSecret, token string) (TokenResponse, error) {
+	q := url.Values{
+		"grant_type":        {"fb_exchange_token"},
+		"client_id":         {appID},
+		"client_secret":     {appSecret},
+		"fb_exchange_token": {token},
+	}
+	var t TokenResponse
+	if err := doGraphRequest(ctx, c, http.MethodGet, baseURL+"oauth/access_token?"+q.Encode(), nil, &t); err != nil {
+		return TokenResponse{}, err
+	}
+	return t, nil
+}
+
+// ExchangeForLongLivedToken exchanges short lived user token (valid for 1 hour) for long lived one (valid for 60 days).
+// Requires AppID and AppSecret
+func (msng *Messenger) ExchangeForLongLivedToken(ctx context.Context, shortLivedToken string) (TokenResponse, error) {
+	if msng.AppAccessToken() == "" {
+		return TokenResponse{}, ErrNoAppCredentials
+	}
+	return exchangeToken(ctx, msng.GetClient(), msng.baseURL(), msng.AppID, msng.AppSecret, shortLivedToken)
+}
+
+// GetPermanentPageToken returns page access token of page with pageID that doesn't expire.
+// Page tokens received with long lived user token don't expire, see ExchangeForLongLivedToken
+func (msng *Messenger) GetPermanentPageToken(ctx context.Context, longLivedUserToken, pageID string) (string, error) {
+	return msng.GetPageAccessToken(ctx, longLivedUserToken, pageID)
+}
+
+// TokenDebugInfo describes access token, see DebugToken
+type TokenDebugInfo struct {
+	AppID               string   `json:"app_id"`
+	Type                string   `json:"type"` // USER, PAGE or APP
+	Application         string   `json:"application"`
+	ExpiresAt           int64    `json:"expires_at"` // Unix time, 0 if token never expires
+	DataAccessExpiresAt int64    `json:"data_access_expires_at"`
+	IsValid             bool     `json:"is_valid"`
+	Scopes              []string `json:"scopes"`
+	UserID              string   `json:"user_id"`
+	ProfileID           string   `json:"profile_id"` // page ID for page tokens
+}
+
+// Expires returns time when token expires, zero time if it never expires
+func (i TokenDebugInfo) Expires() time.Time {
+	if i.ExpiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(i.ExpiresAt, 0)
+}
+
+// DebugToken returns information about token, use it to check token validity on startup.
+// Token is inspected with app access token if AppSecret is set, otherwise with messenger's access token
+func (msng *Messenger) DebugToken(ctx context.Context, token string) (TokenDebugInfo, error) {
+	inspector := msng.AppAccessToken()
+	if inspector == "" && msng.AppSecret != "" { // app ID not set
+		info, err := msng.cachedPageInfo(ctx)
+		if err != nil {
+			return TokenDebugInfo{}, err
+		}
+		inspector = info.AppID + "|" + msng.AppSecret
+	}
+	if inspector == "" {
+		var err error
+		if inspector, err = msng.accessToken(ctx); err != nil {
+			return TokenDebugInfo{}, err
+		}
+	}
+
+	var reply struct {
+		Data TokenDebugInfo `json:"data"`
+	}
+	u := msng.graphURL("debug_token", url.Values{"input_token": {token}}, inspector)
+	if err := doGraphRequest(ctx, msng.GetClient(), http.MethodGet, u, nil, &reply); err != nil {
+		return TokenDebugInfo{}, err
+	}
+	return reply.Data, nil
+}
@@ -0,0 +1,108 @@
+package messenger_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+type failingTokenProvider struct{}
+
+func (failingTokenProvider) GetAccessToken(ctx context.Context) (string, error) {
+	return "", errors.New("token expired")
+}
+
+func TestAccessTokenProvider(t *testing.T) {
+	t.Parallel()
+	var token string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		token = r.FormValue("access_token")
+		w.Write([]byte(`{"recipient_id":"12123213123","message_id":"mid.1"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock, messenger.WithAccessTokenProvider(messenger.StaticTokenProvider("PROVIDED")))
+	if _, err := msng.SendTextMessage(12123213123, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if token != "PROVIDED" {
+		t.Error("Expected provided token, sent", token)
+	}
+
+	msng = messenger.New("XXXXXXX", "", mock, messenger.WithAccessTokenProvider(failingTokenProvider{}))
+	if _, err := msng.SendTextMessage(12123213123, "hello"); !errors.Is(err, messenger.ErrTokenRefresh) {
+		t.Error("Expected ErrTokenRefresh, returned", err)
+	}
+}
+
+func TestLongLivedTokenProvider(t *testing.T) {
+	t.Parallel()
+	exchanges := 0
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/access_token" {
+			exchanges++
+			w.Write([]byte(`{"access_token":"LONG_LIVED","token_type":"bearer"}`))
+			return
+		}
+		w.Write([]byte(`{"recipient_id":"12123213123","message_id":"mid.1"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	msng.AppID, msng.AppSecret = "app", "secret"
+	p := msng.LongLivedTokenProvider("SHORT")
+	for i := 0; i < 2; i++ {
+		token, err := p.GetAccessToken(context.Background())
+		if err != nil || token != "LONG_LIVED" {
+			t.Error("Expected LONG_LIVED, returned", token, err)
+		}
+	}
+	if exchanges != 1 {
+		t.Error("Expected single exchange, made", exchanges)
+	}
+}
+
+func TestExchangeForLongLivedToken(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/access_token":
+			if r.FormValue("grant_type") != "fb_exchange_token" || r.FormValue("fb_exchange_token") != "SHORT" || r.FormValue("client_id") != "APP_ID" {
+				t.Error("Unexpected exchange request", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"access_token":"LONG_LIVED","token_type":"bearer","expires_in":5183944}`))
+		case "/me/accounts":
+			w.Write([]byte(`{"data":[{"id":"PAGE_ID","access_token":"PERMANENT"}]}`))
+		case "/debug_token":
+			if r.FormValue("access_token") != "APP_ID|SECRET" || r.FormValue("input_token") != "PERMANENT" {
+				t.Error("Unexpected debug token request", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"data":{"app_id":"APP_ID","type":"PAGE","is_valid":true,"expires_at":0,"scopes":["pages_messaging"],"profile_id":"PAGE_ID"}}`))
+		default:
+			t.Error("Unexpected path", r.URL.Path)
+		}
+	})
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "", mock)
+	if _, err := msng.ExchangeForLongLivedToken(ctx, "SHORT"); err != messenger.ErrNoAppCredentials {
+		t.Error("Expected ErrNoAppCredentials, returned", err)
+	}
+
+	msng.AppID, msng.AppSecret = "APP_ID", "SECRET"
+	long, err := msng.ExchangeForLongLivedToken(ctx, "SHORT")
+	if err != nil || long.AccessToken != "LONG_LIVED" || long.ExpiresIn != 5183944 {
+		t.Fatal("Unexpected token", long, err)
+	}
+
+	page, err := msng.GetPermanentPageToken(ctx, long.AccessToken, "PAGE_ID")
+	if err != nil || page != "PERMANENT" {
+		t.Fatal("Unexpected page token", page, err)
+	}
+
+	info, err := msng.DebugToken(ctx, page)
+	if err != nil || !info.IsValid || info.Type != "PAGE" || info.ProfileID != "PAGE_ID" || !info.Expires().IsZero() {
+		t.Error("Unexpected token info", info, err)
+	}
+}
@@ -0,0 +1,61 @@
+package messenger
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// WithHTTP2 makes messenger use HTTP/2 for all API calls. HTTP/2 sends concurrent requests over single connection,
+// which reduces connection overhead for bots with high send rates
+func WithHTTP2() Option {
+	return func(msng *Messenger) {
+		msng.configureTransport(func(t *http.Transport) {
+			t.ForceAttemptHTTP2 = true
+		})
+	}
+}
+
+// WithHTTP1Only disables HTTP/2 for all API calls, for environments (e.g. proxies) where HTTP/2 causes issues
+func WithHTTP1Only() Option {
+	return func(msng *Messenger) {
+		msng.configureTransport(func(t *http.Transport) {
+			t.ForceAttemptHTTP2 = false
+			t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{} // non nil map disables HTTP/2
+		})
+	}
+}
+
+// configureTransport sets messenger's transport to configured copy of its *http.Transport.
+// Base of DebugTransport is configured and DebugTransport is kept, other round trippers are replaced
+func (msng *Messenger) configureTransport(configure func(t *http.Transport)) {
+	msng.setTransport(configuredTransport(msng.GetClient().Transport, configure))
+}
+
+func configuredTransport(rt http.RoundTripper, configure func(t *http.Transport)) http.RoundTripper {
+	switch rt := rt.(type) {
+	case *http.Transport:
+		t := rt.Clone()
+		configure(t)
+		return t
+	case *DebugTransport:
+		d := *rt
+		d.Base = configuredTransport(rt.Base, configure)
+		return &d
+	}
+
+	// http.DefaultTransport might be replaced by the app, e.g. with tracing wrapper
+	t, ok := http.DefaultTransport.(*http.Transport)
+	if ok {
+		t = t.Clone()
+	} else {
+		t = &http.Transport{}
+	}
+	configure(t)
+	return t
+}
+
+func (msng *Messenger) setTransport(t http.RoundTripper) {
+	c := *msng.GetClient() // copy, don't change client that might be shared with the rest of the app
+	c.Transport = t
+	msng.HttpClient = &c
+}
@@ -0,0 +1,55 @@
+package messenger_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestHTTPVersionOptions(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "", messenger.WithHTTP2())
+	tr, ok := msng.GetClient().Transport.(*http.Transport)
+	if !ok || !tr.ForceAttemptHTTP2 || tr.TLSNextProto != nil {
+		t.Error("Expected HTTP/2 transport", msng.GetClient().Transport)
+	}
+
+	msng = messenger.New("XXXXXXX", "", messenger.WithHTTP1Only())
+	tr, ok = msng.GetClient().Transport.(*http.Transport)
+	if !ok || tr.ForceAttemptHTTP2 || tr.TLSNextProto == nil || len(tr.TLSNextProto) != 0 {
+		t.Error("Expected HTTP/1 only transport", msng.GetClient().Transport)
+	}
+}
+
+func TestHTTPVersionOptionsKeepTransport(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "", messenger.WithDebugTransport(io.Discard), messenger.WithHTTP2())
+	dt, ok := msng.GetClient().Transport.(*messenger.DebugTransport)
+	if !ok {
+		t.Fatal("Expected DebugTransport to be kept", msng.GetClient().Transport)
+	}
+	if tr, ok := dt.Base.(*http.Transport); !ok || !tr.ForceAttemptHTTP2 {
+		t.Error("Expected HTTP/2 base transport", dt.Base)
+	}
+
+	custom := &http.Transport{MaxIdleConnsPerHost: 42}
+	msng = messenger.New("XXXXXXX", "")
+	msng.HttpClient = &http.Client{Transport: custom}
+	messenger.WithHTTP1Only()(&msng)
+	if tr, ok := msng.GetClient().Transport.(*http.Transport); !ok || tr == custom || tr.MaxIdleConnsPerHost != 42 || tr.ForceAttemptHTTP2 {
+		t.Error("Expected configured copy of client transport", msng.GetClient().Transport)
+	}
+
+	// app might replace default transport, e.g. with tracing wrapper
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = roundTripperFunc(defaultTransport.RoundTrip)
+	defer func() { http.DefaultTransport = defaultTransport }()
+	msng = messenger.New("XXXXXXX", "", messenger.WithHTTP2())
+	if tr, ok := msng.GetClient().Transport.(*http.Transport); !ok || !tr.ForceAttemptHTTP2 {
+		t.Error("Expected new HTTP/2 transport", msng.GetClient().Transport)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -0,0 +1,96 @@
+package messenger
+
+import (
+	"context"
+	"sync"
+)
+
+// TypingManager shows single typing indicator per user when several goroutines reply to the same user,
+// so typing indicator doesn't flicker. Typing indicator is turned off when the last of them stops typing
+type TypingManager struct {
+	mu    sync.Mutex
+	users map[string]*typingState
+}
+
+type typingState struct {
+	mu    sync.Mutex // held while typing action is sent
+	count int        // number of goroutines typing, guarded by mu
+	refs  int        // number of goroutines using the state, guarded by TypingManager.mu
+}
+
+// NewTypingManager creates TypingManager
+func NewTypingManager() *TypingManager {
+	return &TypingManager{users: map[string]*typingState{}}
+}
+
+// WithTypingManager adds TypingManager to messenger, see Messenger.StartTyping
+func WithTypingManager() Option {
+	return func(msng *Messenger) {
+		msng.TypingManager = NewTypingManager()
+	}
+}
+
+// StartTyping shows typing indicator to userID unless it is already shown. Call returned stop function
+// when reply is sent, typing indicator is turned off when all callers for the user stop typing
+func (tm *TypingManager) StartTyping(ctx context.Context, msng *Messenger, userID string) (stop func(), err error) {
+	u := tm.acquire(userID)
+	u.mu.Lock()
+	if u.count == 0 {
+		if err := msng.SendAction(ctx, userID, SenderActionTypingOn); err != nil {
+			u.mu.Unlock()
+			tm.release(userID, u)
+			return nil, err
+		}
+	}
+	u.count++
+	u.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			u.mu.Lock()
+			u.count--
+			if u.count == 0 {
+				// ctx might be cancelled already, typing indicator should still be turned off
+				msng.SendAction(context.WithoutCancel(ctx), userID, SenderActionTypingOff)
+			}
+			u.mu.Unlock()
+			tm.release(userID, u)
+		})
+	}, nil
+}
+
+func (tm *TypingManager) acquire(userID string) *typingState {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	u, ok := tm.users[userID]
+	if !ok {
+		u = &typingState{}
+		tm.users[userID] = u
+	}
+	u.refs++
+	return u
+}
+
+func (tm *TypingManager) release(userID string, u *typingState) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	u.refs--
+	if u.refs == 0 {
+		delete(tm.users, userID)
+	}
+}
+
+// StartTyping shows typing indicator to userID using messenger's TypingManager, call returned stop function
+// when reply is sent. Without TypingManager typing indicator is shown and turned off by stop unconditionally
+func (msng *Messenger) StartTyping(ctx context.Context, userID string) (stop func(), err error) {
+	if msng.TypingManager != nil {
+		return msng.TypingManager.StartTyping(ctx, msng, userID)
+	}
+	if err := msng.SendAction(ctx, userID, SenderActionTypingOn); err != nil {
+		return nil, err
+	}
+	return func() {
+		msng.SendAction(context.WithoutCancel(ctx), userID, SenderActionTypingOff)
+	}, nil
+}
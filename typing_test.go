@@ -0,0 +1,57 @@
+package messenger_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestTypingManager(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var sent []string
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			SenderAction string `json:"sender_action"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		sent = append(sent, body.SenderAction)
+		mu.Unlock()
+		w.Write([]byte(`{"recipient_id":"123"}`))
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock, messenger.WithTypingManager())
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	stops := make(chan func(), 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stop, err := msng.StartTyping(ctx, "123")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			stops <- stop
+		}()
+	}
+	wg.Wait()
+	close(stops)
+
+	mu.Lock()
+	expectSent(t, sent, "typing_on")
+	mu.Unlock()
+
+	for stop := range stops {
+		stop()
+		stop() // calling stop again has no effect
+	}
+	expectSent(t, sent, "typing_on", "typing_off")
+}
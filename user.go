@@ -0,0 +1,82 @@
+package messenger
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProfileField is user profile field that can be requested with GetUserProfileFields
+type ProfileField string
+
+const (
+	// ProfileFieldFirstName is user's first name
+	ProfileFieldFirstName = ProfileField("first_name")
+
+	// ProfileFieldLastName is user's last name
+	ProfileFieldLastName = ProfileField("last_name")
+
+	// ProfileFieldProfilePic is URL of user's profile picture
+	ProfileFieldProfilePic = ProfileField("profile_pic")
+
+	// ProfileFieldLocale is user's locale, like en_US
+	ProfileFieldLocale = ProfileField("locale")
+
+	// ProfileFieldTimezone is user's timezone, offset from UTC
+	ProfileFieldTimezone = ProfileField("timezone")
+
+	// ProfileFieldGender is user's gender
+	ProfileFieldGender = ProfileField("gender")
+)
+
+// UserProfile received from Facebook, only requested fields are set
+type UserProfile struct {
+	ID         string  `json:"id"`
+	FirstName  string  `json:"first_name"`
+	LastName   string  `json:"last_name"`
+	ProfilePic string  `json:"profile_pic"`
+	Locale     string  `json:"locale"`
+	Timezone   float64 `json:"timezone"`
+	Gender     string  `json:"gender"`
+}
+
+// GetUserProfile returns first name, last name and profile picture of user
+func (msng *Messenger) GetUserProfile(ctx context.Context, userID string) (UserProfile, error) {
+	return msng.GetUserProfileFields(ctx, userID, ProfileFieldFirstName, ProfileFieldLastName, ProfileFieldProfilePic)
+}
+
+// GetUserProfileFields returns user profile with requested fields
+func (msng *Messenger) GetUserProfileFields(ctx context.Context, userID string, fields ...ProfileField) (UserProfile, error) {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = string(f)
+	}
+
+	var p UserProfile
+	q := url.Values{"fields": {strings.Join(names, ",")}}
+	err := msng.graphRequest(ctx, http.MethodGet, url.PathEscape(userID), q, nil, &p)
+	return p, err
+}
+
+// DisplayName returns user's full name, or just first or last name if other is not set,
+// "there" is returned when profile has no name (e.g. for "Hi, there!")
+func (p UserProfile) DisplayName() string {
+	if name := strings.TrimSpace(p.FirstName + " " + p.LastName); name != "" {
+		return name
+	}
+	return "there"
+}
+
+// GreetingName returns user's first name, or "there" if first name is not set
+func (p UserProfile) GreetingName() string {
+	if name := strings.TrimSpace(p.FirstName); name != "" {
+		return name
+	}
+	return "there"
+}
+
+// IsEmpty returns true if no profile field is set, e.g. when Facebook returned no data
+func (p UserProfile) IsEmpty() bool {
+	return p == UserProfile{}
+}
@@ -0,0 +1,61 @@
+package messenger_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestUserProfileNames(t *testing.T) {
+	tests := []struct {
+		profile        messenger.UserProfile
+		display, greet string
+	}{
+		{messenger.UserProfile{FirstName: "John", LastName: "Doe"}, "John Doe", "John"},
+		{messenger.UserProfile{FirstName: "John"}, "John", "John"},
+		{messenger.UserProfile{LastName: "Doe"}, "Doe", "there"},
+		{messenger.UserProfile{ID: "1"}, "there", "there"},
+	}
+	for _, tt := range tests {
+		if name := tt.profile.DisplayName(); name != tt.display {
+			t.Errorf("Expected display name %q, returned %q", tt.display, name)
+		}
+		if name := tt.profile.GreetingName(); name != tt.greet {
+			t.Errorf("Expected greeting name %q, returned %q", tt.greet, name)
+		}
+	}
+
+	if !(messenger.UserProfile{}).IsEmpty() || (messenger.UserProfile{ID: "1"}).IsEmpty() {
+		t.Error("Unexpected IsEmpty result")
+	}
+}
+
+func TestGetUserProfileFields(t *testing.T) {
+	t.Parallel()
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/12123213123" {
+			t.Error("Unexpected path", r.URL.Path)
+		}
+		switch r.FormValue("fields") {
+		case "locale,timezone":
+			w.Write([]byte(`{"id":"12123213123","locale":"de_DE","timezone":2}`))
+		case "first_name,last_name,profile_pic":
+			w.Write([]byte(`{"id":"12123213123","first_name":"John","last_name":"Doe","profile_pic":"https://example.com/pic.jpg"}`))
+		default:
+			t.Error("Unexpected fields", r.FormValue("fields"))
+		}
+	})
+
+	msng := messenger.New("XXXXXXX", "", mock)
+	p, err := msng.GetUserProfileFields(context.Background(), "12123213123", messenger.ProfileFieldLocale, messenger.ProfileFieldTimezone)
+	if err != nil || p.Locale != "de_DE" || p.Timezone != 2 || p.FirstName != "" {
+		t.Error("Unexpected profile", p, err)
+	}
+
+	p, err = msng.GetUserProfile(context.Background(), "12123213123")
+	if err != nil || p.DisplayName() != "John Doe" || p.ProfilePic != "https://example.com/pic.jpg" {
+		t.Error("Unexpected profile", p, err)
+	}
+}
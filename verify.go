@@ -0,0 +1,76 @@
+package messenger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RequiredWebhookFields are webhook fields page must be subscribed to, checked by Verify
+var RequiredWebhookFields = []string{WebhookFieldMessages, WebhookFieldMessagingPostbacks}
+
+// VerificationError is returned by Verify when messenger is not configured properly
+type VerificationError struct {
+	InvalidToken         bool     // access token is not valid
+	TokenPageID          string   // page ID of access token, set if it doesn't match messenger's PageID
+	MissingSubscriptions []string // required webhook fields page is not subscribed to
+}
+
+// Error implements error interface
+func (err *VerificationError) Error() string {
+	var problems []string
+	if err.InvalidToken {
+		problems = append(problems, "invalid access token")
+	}
+	if err.TokenPageID != "" {
+		problems = append(problems, fmt.Sprintf("access token is for page %s", err.TokenPageID))
+	}
+	if len(err.MissingSubscriptions) > 0 {
+		problems = append(problems, "missing webhook subscriptions "+strings.Join(err.MissingSubscriptions, ", "))
+	}
+	return "messenger: verification failed: " + strings.Join(problems, "; ")
+}
+
+// Verify checks that access token is valid page token for messenger's PageID and that page is subscribed
+// to RequiredWebhookFields. New doesn't call Graph API, call Verify on startup to catch configuration errors early.
+// Configuration problems are returned as *VerificationError
+func (msng *Messenger) Verify(ctx context.Context) error {
+	token, err := msng.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	info, err := msng.DebugToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	if !info.IsValid {
+		return &VerificationError{InvalidToken: true}
+	}
+
+	var verr VerificationError
+	pageID := info.ProfileID // subscriptions are checked for token's page, events of that page are received
+	if pageID == "" {
+		pageID = msng.PageID
+	} else if msng.PageID != "" && msng.PageID != pageID {
+		verr.TokenPageID = pageID
+	}
+
+	subscribed, err := msng.GetPageWebhookSubscriptions(ctx, pageID)
+	if err != nil {
+		return err
+	}
+	has := map[string]bool{}
+	for _, f := range subscribed {
+		has[f] = true
+	}
+	for _, f := range RequiredWebhookFields {
+		if !has[f] {
+			verr.MissingSubscriptions = append(verr.MissingSubscriptions, f)
+		}
+	}
+
+	if verr.TokenPageID != "" || len(verr.MissingSubscriptions) > 0 {
+		return &verr
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package messenger_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestVerifyConfiguration(t *testing.T) {
+	t.Parallel()
+	valid, subscribed := "true", `["messages"]`
+	mock := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/me":
+			w.Write([]byte(`{"id":"PAGE_ID","name":"Page"}`))
+		case "/app":
+			w.Write([]byte(`{"id":"APP_ID"}`))
+		case "/debug_token":
+			w.Write([]byte(`{"data":{"app_id":"APP_ID","is_valid":` + valid + `,"profile_id":"PAGE_ID"}}`))
+		case "/PAGE_ID/subscribed_apps":
+			w.Write([]byte(`{"data":[{"id":"APP_ID","subscribed_fields":` + subscribed + `}]}`))
+		default:
+			t.Error("Unexpected path", r.URL.Path)
+		}
+	})
+
+	ctx := context.Background()
+	msng := messenger.New("XXXXXXX", "OTHER_PAGE", mock)
+	var verr *messenger.VerificationError
+	if err := msng.Verify(ctx); !errors.As(err, &verr) {
+		t.Fatal("Expected VerificationError, returned", err)
+	}
+	if verr.InvalidToken || verr.TokenPageID != "PAGE_ID" || !reflect.DeepEqual(verr.MissingSubscriptions, []string{"messaging_postbacks"}) {
+		t.Error("Unexpected verification error", verr)
+	}
+
+	subscribed = `["messages","messaging_postbacks","message_reads"]`
+	msng = messenger.New("XXXXXXX", "PAGE_ID", mock)
+	if err := msng.Verify(ctx); err != nil {
+		t.Error("Expected successful verification, returned", err)
+	}
+
+	valid = "false"
+	if err := msng.Verify(ctx); !errors.As(err, &verr) || !verr.InvalidToken {
+		t.Error("Expected invalid token, returned", err)
+	}
+}
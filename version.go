@@ -0,0 +1,128 @@
+package messenger
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DefaultAPIVersion is Graph API version used when messenger's APIVersion is not set
+const DefaultAPIVersion = "v2.6"
+
+// Feature is Messenger Platform feature available only since some Graph API version, see FeatureSupported
+type Feature string
+
+const (
+	// FeatureOTN is one time notification request, see NewOneTimeNotifRequest
+	FeatureOTN = Feature("one_time_notification")
+)
+
+// featureMinVersions is compatibility matrix of features and minimal Graph API version that supports them,
+// features not listed are supported by DefaultAPIVersion
+var featureMinVersions = map[Feature]string{
+	FeatureOTN: "v6.0",
+}
+
+// messageFeatures maps message type names to feature they require
+var messageFeatures = map[string]Feature{
+	"OneTimeNotifRequestMessage": FeatureOTN,
+}
+
+// WithAPIVersion sets Graph API version used by messenger, e.g. "v6.0"
+func WithAPIVersion(version string) Option {
+	return func(msng *Messenger) {
+		msng.APIVersion = version
+	}
+}
+
+// apiVersion returns Graph API version used by messenger
+func (msng *Messenger) apiVersion() string {
+	if msng.APIVersion == "" {
+		return DefaultAPIVersion
+	}
+	return msng.APIVersion
+}
+
+// FeatureSupported reports if feature is supported by Graph API version messenger uses
+func (msng *Messenger) FeatureSupported(feature Feature) bool {
+	return checkFeature(msng.apiVersion(), feature) == nil
+}
+
+// checkFeature returns ErrFeatureNotSupported if feature is not supported by apiVersion
+func checkFeature(apiVersion string, feature Feature) error {
+	minVersion, ok := featureMinVersions[feature]
+	if !ok {
+		return nil
+	}
+	older, err := versionLess(apiVersion, minVersion)
+	if err != nil {
+		return err
+	}
+	if older {
+		return ErrFeatureNotSupported{Feature: feature, RequiredVersion: minVersion, CurrentVersion: apiVersion}
+	}
+	return nil
+}
+
+// VersionConstraint returns check that fails if messenger's API version is older than minVersion
+func (msng *Messenger) VersionConstraint(minVersion string) func(m Message) error {
+	return func(m Message) error {
+		return checkVersion(msng.apiVersion(), minVersion, messageTypeName(m))
+	}
+}
+
+// ValidateMessageForVersion returns ErrFeatureNotSupported if message type m is not supported by apiVersion,
+// returned error matches ErrUnsupportedAPIVersion
+func ValidateMessageForVersion(apiVersion string, m Message) error {
+	feature, ok := messageFeatures[messageTypeName(m)]
+	if !ok {
+		return nil
+	}
+	return checkFeature(apiVersion, feature)
+}
+
+func checkVersion(apiVersion, minVersion, name string) error {
+	older, err := versionLess(apiVersion, minVersion)
+	if err != nil {
+		return err
+	}
+	if older {
+		return fmt.Errorf("%w: %s requires %s, using %s", ErrUnsupportedAPIVersion, name, minVersion, apiVersion)
+	}
+	return nil
+}
+
+func messageTypeName(m Message) string {
+	if m == nil {
+		return ""
+	}
+	return reflect.Indirect(reflect.ValueOf(m)).Type().Name()
+}
+
+// versionLess reports if Graph API version a is older than b, versions are in "v2.6" format
+func versionLess(a, b string) (bool, error) {
+	am, an, err := parseVersion(a)
+	if err != nil {
+		return false, err
+	}
+	bm, bn, err := parseVersion(b)
+	if err != nil {
+		return false, err
+	}
+	return am < bm || am == bm && an < bn, nil
+}
+
+func parseVersion(v string) (major, minor int, err error) {
+	s := strings.TrimPrefix(v, "v")
+	majorStr, minorStr, _ := strings.Cut(s, ".")
+	if major, err = strconv.Atoi(majorStr); err != nil {
+		return 0, 0, fmt.Errorf("messenger: invalid API version %q", v)
+	}
+	if minorStr != "" {
+		if minor, err = strconv.Atoi(minorStr); err != nil {
+			return 0, 0, fmt.Errorf("messenger: invalid API version %q", v)
+		}
+	}
+	return major, minor, nil
+}
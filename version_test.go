@@ -0,0 +1,72 @@
+package messenger_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestValidateMessageForVersion(t *testing.T) {
+	otn := messenger.NewOneTimeNotifRequest("42", "Price drop", "PRICE_DROP")
+
+	if err := messenger.ValidateMessageForVersion("v2.6", &otn); !errors.Is(err, messenger.ErrUnsupportedAPIVersion) {
+		t.Error("Expected ErrUnsupportedAPIVersion for v2.6, returned", err)
+	}
+	for _, v := range []string{"v6.0", "v10.0", "v17.0"} {
+		if err := messenger.ValidateMessageForVersion(v, otn); err != nil {
+			t.Error("Expected OTN to pass on", v, "returned", err)
+		}
+	}
+	text := messenger.TextMessage{}
+	if err := messenger.ValidateMessageForVersion("v2.6", &text); err != nil {
+		t.Error("Expected text message to pass on v2.6, returned", err)
+	}
+
+	msng := messenger.New("XXXXXXX", "", messenger.WithAPIVersion("v5.0"))
+	if err := msng.VersionConstraint("v5.0")(&text); err != nil {
+		t.Error("Expected v5.0 to satisfy v5.0, returned", err)
+	}
+	if err := msng.VersionConstraint("v11.0")(&text); !errors.Is(err, messenger.ErrUnsupportedAPIVersion) {
+		t.Error("Expected ErrUnsupportedAPIVersion, returned", err)
+	}
+}
+
+func TestFeatureSupported(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+	if msng.FeatureSupported(messenger.FeatureOTN) {
+		t.Error("Expected OTN not supported on default API version")
+	}
+	msng.APIVersion = "v6.0"
+	if !msng.FeatureSupported(messenger.FeatureOTN) {
+		t.Error("Expected OTN supported on v6.0")
+	}
+
+	otn := messenger.NewOneTimeNotifRequest("42", "Price drop", "PRICE_DROP")
+	var featureErr messenger.ErrFeatureNotSupported
+	if err := messenger.ValidateMessageForVersion("v5.0", &otn); !errors.As(err, &featureErr) {
+		t.Fatal("Expected ErrFeatureNotSupported, returned", err)
+	}
+	expected := messenger.ErrFeatureNotSupported{Feature: messenger.FeatureOTN, RequiredVersion: "v6.0", CurrentVersion: "v5.0"}
+	if featureErr != expected {
+		t.Error("Expected", expected, "returned", featureErr)
+	}
+}
+
+func TestSendMessageUnsupportedVersion(t *testing.T) {
+	fb.Reset()
+
+	otn := messenger.NewOneTimeNotifRequest("42", "Price drop", "PRICE_DROP")
+	msng := messenger.New("XXXXXXX", "")
+	if _, err := msng.SendMessage(&otn); !errors.Is(err, messenger.ErrUnsupportedAPIVersion) {
+		t.Error("Expected ErrUnsupportedAPIVersion, returned", err)
+	}
+	if len(fb.Calls()) != 0 {
+		t.Error("Expected no request to Facebook, sent", fb.Calls())
+	}
+
+	msng.APIVersion = "v6.0"
+	if _, err := msng.SendMessage(&otn); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,42 @@
+package messenger
+
+import "net/http"
+
+// WebhookVerifyMiddleware answers Facebook webhook verification requests, all other requests are passed to next handler.
+// Use it when Messenger is not your root handler, with http.ServeMux or any other router
+//
+//	http.Handle("/mychatbot", messenger.WebhookVerifyMiddleware(verifyToken)(myHandler))
+func WebhookVerifyMiddleware(verifyToken string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.FormValue("hub.mode") != "subscribe" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.FormValue("hub.verify_token") != verifyToken {
+				http.Error(w, "invalid verify token", http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(r.FormValue("hub.challenge")))
+		})
+	}
+}
+
+// WebhookVerifyHandler returns handler that only answers webhook verification GET requests, other GET requests
+// get empty 200 OK response so it can be used as health check and other methods get 405 Method Not Allowed.
+// Use it when verification and events are routed separately, events are still handled by Messenger
+//
+//	mux.Handle("GET /mychatbot", msng.WebhookVerifyHandler())
+//	mux.Handle("POST /mychatbot", &msng)
+func (msng *Messenger) WebhookVerifyHandler() http.Handler {
+	verify := WebhookVerifyMiddleware(msng.VerifyToken)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		verify.ServeHTTP(w, r)
+	})
+}
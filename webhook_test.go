@@ -0,0 +1,154 @@
+package messenger_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mileusna/facebook-messenger"
+)
+
+func TestWebhookVerifyMiddleware(t *testing.T) {
+	nextCalled := false
+	h := messenger.WebhookVerifyMiddleware(verifyToken)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/?hub.mode=subscribe&hub.challenge=1122334455&hub.verify_token="+verifyToken, nil))
+	if rec.Body.String() != "1122334455" || nextCalled {
+		t.Error("Challenge failed, returned", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/?hub.mode=subscribe&hub.challenge=1122334455&hub.verify_token=wrong", nil))
+	if rec.Code != http.StatusForbidden || nextCalled {
+		t.Error("Expected 403 for wrong token, returned", rec.Code)
+	}
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", nil))
+	if !nextCalled {
+		t.Error("POST request not passed to next handler")
+	}
+}
+
+func TestWebhookVerifyHandler(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "")
+	msng.VerifyToken = verifyToken
+	h := msng.WebhookVerifyHandler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/?hub.mode=subscribe&hub.challenge=1122334455&hub.verify_token="+verifyToken, nil))
+	if rec.Body.String() != "1122334455" {
+		t.Error("Challenge failed, returned", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Error("Expected 200 for health check, returned", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage)))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != http.MethodGet {
+		t.Error("Expected 405 for POST, returned", rec.Code)
+	}
+}
+
+func TestFeedReceived(t *testing.T) {
+	received := make(chan messenger.FacebookFeedEntry, 1)
+	msng := messenger.New("XXXXXXX", "1")
+	msng.FeedReceived = func(msng *messenger.Messenger, e messenger.FacebookFeedEntry) {
+		received <- e
+	}
+
+	feed := `{"object":"page","entry":[{"id":1,"time":1520383571,"changes":[` +
+		`{"field":"feed","value":{"item":"comment","verb":"add","post_id":"1_2","comment_id":"2_3","message":"nice"}}]}]}`
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(feed)))
+
+	e := <-received
+	if e.ID != "1" || len(e.Changes) != 1 {
+		t.Fatal("Unexpected feed entry", e)
+	}
+	if v := e.Changes[0].Value; v.PostID != "1_2" || v.CommentID != "2_3" || v.Item != "comment" {
+		t.Error("Unexpected feed change", v)
+	}
+
+	// feed entry with empty messaging array is still feed entry
+	feed = strings.Replace(feed, `"changes"`, `"messaging":[],"changes"`, 1)
+	msng.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(feed)))
+	select {
+	case e := <-received:
+		if len(e.Changes) != 1 {
+			t.Error("Unexpected feed entry", e)
+		}
+	case <-time.After(time.Second):
+		t.Error("Feed entry with empty messaging not received")
+	}
+}
+
+func TestInvalidWebhookRequest(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "1")
+	tests := []string{
+		`{"entry":[{"id":1,"time":1458692752478,"messaging":[{"sender":{"id":"1"},"recipient":{"id":"2"},"message":{"text":"hi"}}]}]}`,
+		`{"object":"user","entry":[{"id":1,"time":1458692752478,"messaging":[{"sender":{"id":"1"},"recipient":{"id":"2"},"message":{"text":"hi"}}]}]}`,
+		`{"object":"page","entry":[]}`,
+		`{"object":"page"}`,
+		`{"object":"page","entry":[{"id":1,"time":1458692752478,"messaging":null}]}`,
+		`{"object":"page","entry":[`,
+		`not json`,
+	}
+	for _, body := range tests {
+		rec := httptest.NewRecorder()
+		msng.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, returned %d", body, rec.Code)
+		}
+		if !strings.HasPrefix(rec.Body.String(), `{"error":"messenger: invalid webhook request`) {
+			t.Error("Unexpected error body", rec.Body.String())
+		}
+	}
+}
+
+func TestOnDecodeError(t *testing.T) {
+	msng := messenger.New("XXXXXXX", "1")
+	var decodeErr error
+	msng.OnDecodeError = func(w http.ResponseWriter, r *http.Request, err error) {
+		decodeErr = err
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	rec := httptest.NewRecorder()
+	msng.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"object":`)))
+	if rec.Code != http.StatusUnprocessableEntity || !errors.Is(decodeErr, messenger.ErrInvalidRequest) {
+		t.Error("Expected OnDecodeError called, returned", rec.Code, decodeErr)
+	}
+
+	decodeErr = nil
+	rec = httptest.NewRecorder()
+	msng.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"object":"user","entry":[]}`)))
+	if rec.Code != http.StatusUnprocessableEntity || !errors.Is(decodeErr, messenger.ErrInvalidRequest) {
+		t.Error("Expected OnDecodeError called for invalid request, returned", rec.Code, decodeErr)
+	}
+
+	body := `{"object":"page","unknown":1,"entry":[]}`
+	if _, err := messenger.DecodeRequestStrict(httptest.NewRequest("POST", "/", strings.NewReader(body))); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+	if _, err := messenger.DecodeRequest(httptest.NewRequest("POST", "/", strings.NewReader(body))); err != nil {
+		t.Error(err)
+	}
+
+	nested := `{"object":"page","entry":[{"id":"1","time":1458692752478,"messaging":[{"sender":{"id":"42"},"recipient":{"id":"1"},
+		"timestamp":1458692752478,"unknown":1,"message":{"mid":"mid.1","text":"hello"}}]}]}`
+	if _, err := messenger.DecodeRequestStrict(httptest.NewRequest("POST", "/", strings.NewReader(nested))); err == nil {
+		t.Error("Expected error for unknown field in messaging event")
+	}
+	if _, err := messenger.DecodeRequestStrict(httptest.NewRequest("POST", "/", strings.NewReader(webhookMessage))); err != nil {
+		t.Error("Expected known fields decoded, returned", err)
+	}
+}
@@ -2,8 +2,8 @@ package messenger
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"log"
 	"net/http"
 )
 
@@ -41,9 +41,9 @@ func (msng *Messenger) DeleteWelcome() error {
 
 func (msng *Messenger) setWelcome(m interface{}) error {
 
-	if msng.pageURL == "" {
-		msng.pageURL = apiURL + msng.PageID + "/thread_settings?access_token=" + msng.AccessToken
-		log.Println(msng.pageURL)
+	token, err := msng.accessToken(context.Background())
+	if err != nil {
+		return err
 	}
 
 	w := welcome{
@@ -58,8 +58,8 @@ func (msng *Messenger) setWelcome(m interface{}) error {
 	}
 
 	s, _ := json.Marshal(w)
-	log.Println("MESSAGE:", string(s))
-	req, err := http.NewRequest("POST", msng.pageURL, bytes.NewBuffer(s))
+	msng.logger().Debug("setting welcome message", "body", string(s))
+	req, err := http.NewRequest("POST", msng.graphURL(msng.PageID+"/thread_settings", nil, token), bytes.NewBuffer(s))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := msng.GetClient().Do(req)
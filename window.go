@@ -0,0 +1,69 @@
+package messenger
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// messagingWindow is standard messaging window after user's interaction
+const messagingWindow = 24 * time.Hour
+
+// Reasons returned by CanSendMessage
+const (
+	ReasonWindowOpen        = "24h_window_open"
+	ReasonWindowClosed      = "24h_window_closed"
+	ReasonProfileAccessible = "profile_accessible"
+)
+
+// InteractionLog is implemented by event logs that can tell when user last interacted with the page.
+// MemoryEventLog implements it
+type InteractionLog interface {
+	LastInteraction(ctx context.Context, userID string) (t time.Time, ok bool, err error)
+}
+
+// CanSendMessage reports whether standard 24 hour messaging window with user is open.
+// If EventLog implements InteractionLog last interaction time is used, otherwise user profile is requested
+// which fails if messaging is not allowed. When window is closed use tagged message instead
+func (msng *Messenger) CanSendMessage(ctx context.Context, userID string) (bool, string, error) {
+	if il, ok := msng.EventLog.(InteractionLog); ok {
+		t, found, err := il.LastInteraction(ctx, userID)
+		if err != nil {
+			return false, "", err
+		}
+		if found {
+			if time.Since(t) < messagingWindow {
+				return true, ReasonWindowOpen, nil
+			}
+			return false, ReasonWindowClosed, nil
+		}
+	}
+
+	_, err := msng.GetUserProfile(ctx, userID)
+	if errors.Is(err, ErrOutsideMessagingWindow) {
+		return false, ReasonWindowClosed, nil
+	}
+	if err != nil {
+		return false, "", err
+	}
+	return true, ReasonProfileAccessible, nil
+}
+
+// isInteraction reports whether event type is user's interaction that opens messaging window
+func isInteraction(eventType string) bool {
+	return eventType == "message" || eventType == "postback" || eventType == "optin"
+}
+
+// LastInteraction implements InteractionLog, only events kept in the log are checked
+func (l *MemoryEventLog) LastInteraction(ctx context.Context, userID string) (time.Time, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := len(l.events) - 1; i >= 0; i-- {
+		e := l.events[i]
+		if isInteraction(e.Type) && strconv.FormatInt(e.SenderID, 10) == userID {
+			return e.Timestamp, true, nil
+		}
+	}
+	return time.Time{}, false, nil
+}